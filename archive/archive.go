@@ -0,0 +1,371 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+// GenesisHash is the previous hash of the first record of an archive.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+var (
+	ErrChainBroken = errors.New("archive chain is broken")
+	ErrClosed      = errors.New("archive is closed")
+)
+
+// Record is a single archived message. Records are stored as JSON lines, each holding the hash of the
+// previous record, so that any change, removal or reordering of records breaks the chain.
+type Record struct {
+	Sequence     uint64                   `json:"sequence"`
+	ArchivedAt   time.Time                `json:"archivedAt"`
+	PreviousHash string                   `json:"previousHash"`
+	Message      ocmf.Message             `json:"message"`
+	Report       *ocmf.VerificationReport `json:"report,omitempty"`
+	// Hash is the SHA-256 hash of the encoded record. It is stored next to the record, not in it.
+	Hash string `json:"-"`
+}
+
+// Time returns the time of the first reading of the message as stated by the meter,
+// or the archiving time if the message has no readable reading time.
+func (r *Record) Time() time.Time {
+	for _, reading := range r.Message.Payload.Readings {
+		if timestamp, _, err := reading.ParseTime(); err == nil {
+			return timestamp
+		}
+	}
+
+	return r.ArchivedAt
+}
+
+// Truncation is an incomplete last record, which is left behind if writing a record was interrupted, e.g. by a crash.
+// The record was never acknowledged by Append.
+type Truncation struct {
+	// Line is the number of the incomplete line, starting at 1.
+	Line int
+	// Offset is the size of the archive without the incomplete record.
+	Offset int64
+	// Data holds the bytes of the incomplete record.
+	Data []byte
+}
+
+// truncatedError reports an incomplete last record. It is a broken chain, unless the archive is opened with WithRepair.
+type truncatedError struct {
+	Truncation
+}
+
+func (e *truncatedError) Error() string {
+	return fmt.Sprintf("line %d: truncated record: %s", e.Line, ErrChainBroken)
+}
+
+func (e *truncatedError) Is(target error) bool {
+	return target == ErrChainBroken
+}
+
+// line is the stored form of a record. The hash is computed over the exact bytes of the record.
+type line struct {
+	Hash   string          `json:"hash"`
+	Record json.RawMessage `json:"record"`
+}
+
+func hashRecord(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Archive is an append-only file of hash-chained records. It is safe for concurrent use,
+// but the file must not be written by more than one Archive at a time.
+type Archive struct {
+	mu       sync.Mutex
+	file     *os.File
+	lastHash string
+	next     uint64
+	now      func() time.Time
+	repair   func(Truncation)
+}
+
+type Option func(*Archive)
+
+// WithRepair removes an incomplete last record when opening the archive instead of failing with ErrChainBroken.
+// The removed record is passed to report, so it can be logged or kept for analysis. All other damage still fails.
+func WithRepair(report func(Truncation)) Option {
+	return func(a *Archive) {
+		a.repair = report
+	}
+}
+
+// Open opens or creates the archive at path. The chain of an existing archive is verified before new records can be appended.
+func Open(path string, opts ...Option) (*Archive, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+
+	archive := &Archive{
+		file:     file,
+		lastHash: GenesisHash,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(archive)
+	}
+
+	err = Read(file, func(record Record) error {
+		archive.lastHash = record.Hash
+		archive.next = record.Sequence + 1
+		return nil
+	})
+
+	truncated := &truncatedError{}
+	if errors.As(err, &truncated) && archive.repair != nil {
+		err = archive.truncate(truncated.Truncation)
+	}
+
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func (a *Archive) truncate(truncation Truncation) error {
+	if err := a.file.Truncate(truncation.Offset); err != nil {
+		return errors.Wrap(err, "failed to remove truncated record")
+	}
+
+	if err := a.file.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync archive")
+	}
+
+	a.repair(truncation)
+	return nil
+}
+
+// Append stores the message with its verification report, which may be nil, and returns the new record.
+func (a *Archive) Append(message ocmf.Message, report *ocmf.VerificationReport) (*Record, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return nil, ErrClosed
+	}
+
+	record := Record{
+		Sequence:     a.next,
+		ArchivedAt:   a.now().UTC(),
+		PreviousHash: a.lastHash,
+		Message:      message,
+		Report:       report,
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode record")
+	}
+
+	record.Hash = hashRecord(data)
+
+	// The record is embedded as is, as re-encoding it could change the hashed bytes
+	encoded := make([]byte, 0, len(data)+len(record.Hash)+24)
+	encoded = append(encoded, `{"hash":"`...)
+	encoded = append(encoded, record.Hash...)
+	encoded = append(encoded, `","record":`...)
+	encoded = append(encoded, data...)
+	encoded = append(encoded, "}\n"...)
+
+	// Written at once, so a record is either stored completely or detected as truncated
+	if _, err := a.file.Write(encoded); err != nil {
+		return nil, errors.Wrap(err, "failed to write record")
+	}
+
+	if err := a.file.Sync(); err != nil {
+		return nil, errors.Wrap(err, "failed to sync archive")
+	}
+
+	a.lastHash = record.Hash
+	a.next++
+	return &record, nil
+}
+
+// Head returns the hash of the last record, which commits to the whole archive.
+func (a *Archive) Head() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.lastHash
+}
+
+// Len returns the number of records.
+func (a *Archive) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return int(a.next)
+}
+
+// Verify checks the chain of the whole archive as stored on disk. Records removed from the end of the archive
+// can only be detected by comparing Head with a previously published value.
+func (a *Archive) Verify() error {
+	return a.read(func(Record) error { return nil })
+}
+
+// Query returns the records matching the query in archive order.
+func (a *Archive) Query(query Query) ([]Record, error) {
+	var records []Record
+	err := a.read(func(record Record) error {
+		if query.Matches(record) {
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	return records, err
+}
+
+// Export writes the records matching the query to w, exactly as they are stored.
+// Every exported record keeps its hash and the hash of its predecessor, so it can be checked against the full archive
+// with ReadRecords.
+func (a *Archive) Export(w io.Writer, query Query) (int, error) {
+	exported := 0
+	err := a.readLines(func(record Record, data []byte) error {
+		if !query.Matches(record) {
+			return nil
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return errors.Wrap(err, "failed to write record")
+		}
+
+		exported++
+		return nil
+	})
+
+	return exported, err
+}
+
+func (a *Archive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return ErrClosed
+	}
+
+	err := a.file.Close()
+	a.file = nil
+	return err
+}
+
+func (a *Archive) read(fn func(Record) error) error {
+	return a.readLines(func(record Record, _ []byte) error {
+		return fn(record)
+	})
+}
+
+// readLines reads the file from the start while holding the lock, so that no record is appended meanwhile.
+func (a *Archive) readLines(fn func(record Record, data []byte) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		return ErrClosed
+	}
+
+	return readChain(io.NewSectionReader(a.file, 0, 1<<62), fn)
+}
+
+// Read reads a complete archive and verifies its chain. fn is called for every record in order.
+func Read(r io.Reader, fn func(Record) error) error {
+	return readChain(r, func(record Record, _ []byte) error {
+		return fn(record)
+	})
+}
+
+// ReadRecords reads records, e.g. an export, and checks the hash of every record, but not the chain between them.
+func ReadRecords(r io.Reader, fn func(Record) error) error {
+	return readLines(r, func(record Record, _ []byte) error {
+		return fn(record)
+	})
+}
+
+func readChain(r io.Reader, fn func(record Record, data []byte) error) error {
+	previousHash := GenesisHash
+	var sequence uint64
+
+	return readLines(r, func(record Record, data []byte) error {
+		switch {
+		case record.Sequence != sequence:
+			return errors.Wrapf(ErrChainBroken, "record %d: expected sequence %d", record.Sequence, sequence)
+		case record.PreviousHash != previousHash:
+			return errors.Wrapf(ErrChainBroken, "record %d: previous hash does not match", record.Sequence)
+		}
+
+		previousHash = record.Hash
+		sequence++
+		return fn(record, data)
+	})
+}
+
+func readLines(r io.Reader, fn func(record Record, data []byte) error) error {
+	reader := bufio.NewReader(r)
+	var offset int64
+
+	for number := 1; ; number++ {
+		data, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(data) > 0 {
+				return &truncatedError{Truncation{Line: number, Offset: offset, Data: data}}
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "failed to read archive")
+		}
+
+		record, err := decodeLine(bytes.TrimSpace(data))
+		if err != nil {
+			return errors.Wrapf(err, "line %d", number)
+		}
+
+		if err := fn(*record, data); err != nil {
+			return err
+		}
+
+		offset += int64(len(data))
+	}
+}
+
+func decodeLine(data []byte) (*Record, error) {
+	stored := line{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, errors.Wrap(ErrChainBroken, err.Error())
+	}
+
+	if hashRecord(stored.Record) != stored.Hash {
+		return nil, errors.Wrap(ErrChainBroken, "record hash does not match")
+	}
+
+	record := Record{}
+	if err := json.Unmarshal(stored.Record, &record); err != nil {
+		return nil, errors.Wrap(ErrChainBroken, err.Error())
+	}
+
+	record.Hash = stored.Hash
+	return &record, nil
+}
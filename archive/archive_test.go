@@ -0,0 +1,339 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type archiveTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	path       string
+}
+
+func (s *archiveTestSuite) SetupTest() {
+	privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+	s.path = filepath.Join(s.T().TempDir(), "meter-values.ocmf")
+}
+
+func (s *archiveTestSuite) buildMessage(meterSerial string, counter int, readingTime string) *ocmf.Message {
+	message, err := ocmf.NewBuilder(s.privateKey).
+		WithPagination(fmt.Sprintf("T%d", counter)).
+		WithMeterSerial(meterSerial).
+		WithIdentificationType(string(ocmf.RfidNone)).
+		AddReading(ocmf.Reading{
+			Time:         readingTime,
+			ReadingValue: float64(counter),
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+	return message
+}
+
+// fill appends two messages per day of two meters, starting at 2024-03-01.
+func (s *archiveTestSuite) fill(archive *Archive) {
+	for day := 1; day <= 3; day++ {
+		for _, meterSerial := range []string{"meterA", "meterB"} {
+			readingTime := fmt.Sprintf("2024-03-%02dT10:00:00,000+0100 S", day)
+			message := s.buildMessage(meterSerial, day, readingTime)
+
+			_, err := archive.Append(*message, message.Report(&s.privateKey.PublicKey))
+			s.Require().NoError(err)
+		}
+	}
+}
+
+func (s *archiveTestSuite) TestAppend() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	s.Equal(GenesisHash, archive.Head())
+
+	message := s.buildMessage("meterA", 1, "2024-03-01T10:00:00,000+0100 S")
+	first, err := archive.Append(*message, message.Report(&s.privateKey.PublicKey))
+	s.Require().NoError(err)
+	s.Equal(uint64(0), first.Sequence)
+	s.Equal(GenesisHash, first.PreviousHash)
+	s.Equal(first.Hash, archive.Head())
+
+	second, err := archive.Append(*message, nil)
+	s.Require().NoError(err)
+	s.Equal(uint64(1), second.Sequence)
+	s.Equal(first.Hash, second.PreviousHash)
+	s.Require().NoError(archive.Close())
+
+	// Reopening continues the chain
+	archive, err = Open(s.path)
+	s.Require().NoError(err)
+	defer archive.Close()
+
+	s.Equal(2, archive.Len())
+	s.Equal(second.Hash, archive.Head())
+
+	third, err := archive.Append(*message, nil)
+	s.Require().NoError(err)
+	s.Equal(uint64(2), third.Sequence)
+	s.Equal(second.Hash, third.PreviousHash)
+	s.NoError(archive.Verify())
+
+	records, err := archive.Query(Query{})
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+	s.Equal(message.String(), records[0].Message.String())
+	s.Require().NotNil(records[0].Report)
+	s.Equal(ocmf.VerdictValid, records[0].Report.Verdict)
+	s.Nil(records[1].Report)
+	s.Equal(third.Hash, records[2].Hash)
+}
+
+func (s *archiveTestSuite) TestQuery() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	defer archive.Close()
+
+	s.fill(archive)
+
+	from := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       Query
+		pagination  []string
+		meterSerial string
+	}{
+		{
+			name:       "All records",
+			query:      Query{},
+			pagination: []string{"T1", "T1", "T2", "T2", "T3", "T3"},
+		},
+		{
+			name:        "Meter serial",
+			query:       Query{MeterSerial: "meterB"},
+			pagination:  []string{"T1", "T2", "T3"},
+			meterSerial: "meterB",
+		},
+		{
+			name:       "Time range",
+			query:      Query{From: from, To: to},
+			pagination: []string{"T2", "T2"},
+		},
+		{
+			name:        "Meter serial and start time",
+			query:       Query{MeterSerial: "meterA", From: from},
+			pagination:  []string{"T2", "T3"},
+			meterSerial: "meterA",
+		},
+		{
+			name:  "Unknown meter",
+			query: Query{MeterSerial: "meterC"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			records, err := archive.Query(tt.query)
+			s.Require().NoError(err)
+
+			var pagination []string
+			for _, record := range records {
+				pagination = append(pagination, record.Message.Payload.Pagination)
+				if tt.meterSerial != "" {
+					s.Equal(tt.meterSerial, record.Message.Payload.MeterSerial)
+				}
+			}
+
+			s.Equal(tt.pagination, pagination)
+		})
+	}
+}
+
+func (s *archiveTestSuite) TestExport() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	defer archive.Close()
+
+	s.fill(archive)
+
+	all, err := archive.Query(Query{})
+	s.Require().NoError(err)
+
+	buffer := bytes.Buffer{}
+	exported, err := archive.Export(&buffer, Query{MeterSerial: "meterA"})
+	s.Require().NoError(err)
+	s.Equal(3, exported)
+
+	// The exported records keep their hashes, which match the full archive
+	var records []Record
+	s.Require().NoError(ReadRecords(bytes.NewReader(buffer.Bytes()), func(record Record) error {
+		records = append(records, record)
+		return nil
+	}))
+	s.Require().Len(records, 3)
+	s.Equal(all[0].Hash, records[0].Hash)
+	s.Equal(all[2].Hash, records[1].Hash)
+	s.Equal(all[1].Hash, records[1].PreviousHash)
+
+	// But do not form a chain on their own
+	err = Read(bytes.NewReader(buffer.Bytes()), func(Record) error { return nil })
+	s.ErrorIs(err, ErrChainBroken)
+
+	tampered := strings.Replace(buffer.String(), "meterA", "meterX", 1)
+	err = ReadRecords(strings.NewReader(tampered), func(Record) error { return nil })
+	s.ErrorIs(err, ErrChainBroken)
+}
+
+func (s *archiveTestSuite) TestVerify_tampered() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	s.fill(archive)
+	s.Require().NoError(archive.Close())
+
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "Changed message",
+			data: strings.Replace(string(data), `\"RV\":2`, `\"RV\":5`, 1),
+		},
+		{
+			name: "Changed record hash",
+			data: strings.Replace(string(data), lines[1][9:73], lines[2][9:73], 1),
+		},
+		{
+			name: "Removed record",
+			data: lines[0] + strings.Join(lines[2:], ""),
+		},
+		{
+			name: "Reordered records",
+			data: lines[1] + lines[0] + strings.Join(lines[2:], ""),
+		},
+		{
+			name: "Truncated record",
+			data: string(data[:len(data)-10]),
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tampered.ocmf")
+			s.Require().NoError(os.WriteFile(path, []byte(tt.data), 0o644))
+
+			_, err := Open(path)
+			s.ErrorIs(err, ErrChainBroken)
+
+			err = Read(strings.NewReader(tt.data), func(Record) error { return nil })
+			s.ErrorIs(err, ErrChainBroken)
+		})
+	}
+}
+
+func (s *archiveTestSuite) TestVerify_modifiedOnDisk() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	defer archive.Close()
+
+	s.fill(archive)
+	s.Require().NoError(archive.Verify())
+
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(s.path, bytes.Replace(data, []byte("meterB"), []byte("meterC"), 1), 0o644))
+
+	s.ErrorIs(archive.Verify(), ErrChainBroken)
+}
+
+func (s *archiveTestSuite) TestOpen_truncatedRecord() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	s.fill(archive)
+	head := archive.Head()
+	s.Require().NoError(archive.Close())
+
+	complete, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+
+	// An interrupted append leaves a partial last line behind
+	message := s.buildMessage("meterA", 4, "2024-03-04T10:00:00,000+0100 S")
+	partial := []byte(`{"hash":"` + strings.Repeat("a", 64) + `","record":{"sequence":6,"message":` + message.String()[:20])
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	s.Require().NoError(err)
+	_, err = file.Write(partial)
+	s.Require().NoError(err)
+	s.Require().NoError(file.Close())
+
+	_, err = Open(s.path)
+	s.ErrorIs(err, ErrChainBroken)
+	s.ErrorContains(err, "line 7: truncated record")
+
+	var truncations []Truncation
+	archive, err = Open(s.path, WithRepair(func(truncation Truncation) {
+		truncations = append(truncations, truncation)
+	}))
+	s.Require().NoError(err)
+	defer archive.Close()
+
+	s.Equal([]Truncation{{Line: 7, Offset: int64(len(complete)), Data: partial}}, truncations)
+	s.Equal(head, archive.Head())
+	s.Equal(6, archive.Len())
+
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.Equal(complete, data)
+
+	// The chain continues after the last complete record
+	record, err := archive.Append(*message, nil)
+	s.Require().NoError(err)
+	s.EqualValues(6, record.Sequence)
+	s.Equal(head, record.PreviousHash)
+	s.NoError(archive.Verify())
+}
+
+func (s *archiveTestSuite) TestOpen_repairKeepsOtherDamage() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	s.fill(archive)
+	s.Require().NoError(archive.Close())
+
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(s.path, bytes.Replace(data, []byte("meterB"), []byte("meterC"), 1), 0o644))
+
+	repaired := false
+	_, err = Open(s.path, WithRepair(func(Truncation) { repaired = true }))
+	s.ErrorIs(err, ErrChainBroken)
+	s.False(repaired)
+}
+
+func (s *archiveTestSuite) TestClose() {
+	archive, err := Open(s.path)
+	s.Require().NoError(err)
+	s.Require().NoError(archive.Close())
+
+	message := s.buildMessage("meterA", 1, "2024-03-01T10:00:00,000+0100 S")
+	_, err = archive.Append(*message, nil)
+	s.ErrorIs(err, ErrClosed)
+	s.ErrorIs(archive.Verify(), ErrClosed)
+	s.ErrorIs(archive.Close(), ErrClosed)
+}
+
+func TestArchive(t *testing.T) {
+	suite.Run(t, new(archiveTestSuite))
+}
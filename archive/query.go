@@ -0,0 +1,29 @@
+package archive
+
+import "time"
+
+// Query selects archived records. Empty fields match all records.
+type Query struct {
+	MeterSerial string
+	// From and To limit the records by Record.Time. From is inclusive, To is exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// Matches returns true if the record is selected by the query.
+func (q Query) Matches(record Record) bool {
+	if q.MeterSerial != "" && record.Message.Payload.MeterSerial != q.MeterSerial {
+		return false
+	}
+
+	recordTime := record.Time()
+	if !q.From.IsZero() && recordTime.Before(q.From) {
+		return false
+	}
+
+	if !q.To.IsZero() && !recordTime.Before(q.To) {
+		return false
+	}
+
+	return true
+}
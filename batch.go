@@ -0,0 +1,285 @@
+package ocmf_go
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type BatchStatus string
+
+const (
+	// BatchStatusVerified is set if the signature of the message is valid.
+	BatchStatusVerified = BatchStatus("VERIFIED")
+	// BatchStatusFailed is set if the signature does not match the payload or the key of the meter.
+	BatchStatusFailed = BatchStatus("FAILED")
+	// BatchStatusInvalid is set if the message could not be parsed or validated.
+	BatchStatusInvalid = BatchStatus("INVALID")
+	// BatchStatusError is set if the message could not be verified, e.g. because the key of the meter is unknown.
+	BatchStatusError = BatchStatus("ERROR")
+)
+
+// BatchResult is the outcome of a single message of the batch.
+type BatchResult struct {
+	// Index is the position of the message in the input.
+	Index int
+	// Message is nil if the message could not be parsed.
+	Message *Message
+	Status  BatchStatus
+	Err     error
+}
+
+// BatchStats summarizes a batch verification run.
+type BatchStats struct {
+	Total    int           `json:"total"`
+	Verified int           `json:"verified"`
+	Failed   int           `json:"failed"`
+	Invalid  int           `json:"invalid"`
+	Errors   int           `json:"errors"`
+	Duration time.Duration `json:"duration"`
+}
+
+// MessagesPerSecond returns the throughput of the run.
+func (s BatchStats) MessagesPerSecond() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+
+	return float64(s.Total) / s.Duration.Seconds()
+}
+
+func (s *BatchStats) add(result BatchResult) {
+	s.Total++
+
+	switch result.Status {
+	case BatchStatusVerified:
+		s.Verified++
+	case BatchStatusFailed:
+		s.Failed++
+	case BatchStatusInvalid:
+		s.Invalid++
+	default:
+		s.Errors++
+	}
+}
+
+// BatchOption configures a BatchVerifier.
+type BatchOption func(*BatchVerifier)
+
+// WithWorkers sets the number of messages verified in parallel. It defaults to GOMAXPROCS.
+func WithWorkers(workers int) BatchOption {
+	return func(v *BatchVerifier) {
+		if workers > 0 {
+			v.workers = workers
+		}
+	}
+}
+
+// WithBatchValidation validates every message before its signature is verified.
+func WithBatchValidation() BatchOption {
+	return func(v *BatchVerifier) {
+		v.validate = true
+	}
+}
+
+// BatchVerifier parses and verifies large numbers of messages with a bounded pool of workers.
+// The key of every message is looked up by its meter serial. It is safe for concurrent use.
+type BatchVerifier struct {
+	resolver KeyResolver
+	workers  int
+	validate bool
+}
+
+func NewBatchVerifier(resolver KeyResolver, opts ...BatchOption) *BatchVerifier {
+	verifier := &BatchVerifier{
+		resolver: resolver,
+		workers:  runtime.GOMAXPROCS(0),
+	}
+
+	for _, opt := range opts {
+		opt(verifier)
+	}
+
+	return verifier
+}
+
+type batchJob struct {
+	index int
+	data  string
+}
+
+// Run verifies the messages read from the channel until it is closed and passes the results to emit in input order.
+// At most a few messages per worker are held in memory, regardless of the size of the batch.
+// If the context is cancelled or emit returns an error, no further messages are read and the error is returned.
+func (v *BatchVerifier) Run(ctx context.Context, messages <-chan string, emit func(BatchResult) error) (BatchStats, error) {
+	if v.resolver == nil {
+		return BatchStats{}, errors.New("key resolver is required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	jobs := make(chan batchJob)
+	results := make(chan BatchResult, v.workers)
+	// Limits the results waiting for an earlier, slower message
+	slots := make(chan struct{}, 2*v.workers)
+
+	go func() {
+		defer close(jobs)
+
+		for index := 0; ; index++ {
+			var data string
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				data = message
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+
+			jobs <- batchJob{index: index, data: data}
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < v.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for job := range jobs {
+				results <- v.verify(ctx, job)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		stats   BatchStats
+		emitErr error
+		next    int
+	)
+	pending := make(map[int]BatchResult)
+	for result := range results {
+		// Keep draining, so that the workers can exit
+		if emitErr != nil || ctx.Err() != nil {
+			<-slots
+			continue
+		}
+
+		pending[result.Index] = result
+		for ctx.Err() == nil {
+			result, found := pending[next]
+			if !found {
+				break
+			}
+
+			delete(pending, next)
+			<-slots
+			next++
+
+			stats.add(result)
+			if err := emit(result); err != nil {
+				emitErr = err
+				cancel()
+				break
+			}
+		}
+	}
+
+	stats.Duration = time.Since(start)
+	if emitErr != nil {
+		return stats, emitErr
+	}
+
+	// Cancelled by the caller
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// VerifyAll verifies the messages and returns the results in input order.
+func (v *BatchVerifier) VerifyAll(ctx context.Context, messages []string) ([]BatchResult, BatchStats, error) {
+	input := make(chan string)
+	go func() {
+		defer close(input)
+
+		for _, message := range messages {
+			select {
+			case <-ctx.Done():
+				return
+			case input <- message:
+			}
+		}
+	}()
+
+	results := make([]BatchResult, 0, len(messages))
+	stats, err := v.Run(ctx, input, func(result BatchResult) error {
+		results = append(results, result)
+		return nil
+	})
+
+	return results, stats, err
+}
+
+func (v *BatchVerifier) verify(ctx context.Context, job batchJob) BatchResult {
+	result := BatchResult{Index: job.index, Status: BatchStatusError}
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	message, err := ParseMessage(job.data)
+	if err != nil {
+		result.Status = BatchStatusInvalid
+		result.Err = err
+		return result
+	}
+
+	result.Message = message
+	if v.validate {
+		if err := message.Validate(); err != nil {
+			result.Status = BatchStatusInvalid
+			result.Err = err
+			return result
+		}
+	}
+
+	publicKey, err := SelectPublicKey(ctx, v.resolver, message.Payload.MeterSerial, nil)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	valid, err := message.Verify(publicKey)
+	switch {
+	case err != nil:
+		result.Err = errors.Wrap(err, "unable to verify signature")
+	case !valid:
+		result.Status = BatchStatusFailed
+		result.Err = ErrVerificationFailure
+	default:
+		result.Status = BatchStatusVerified
+	}
+
+	return result
+}
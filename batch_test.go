@@ -0,0 +1,215 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type batchTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	registry   *KeyRegistry
+}
+
+func (s *batchTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.registry = NewKeyRegistry()
+	s.registry.Register("exampleSerial123", &privateKey.PublicKey)
+}
+
+func (s *batchTestSuite) buildMessage(meterSerial string, counter int) string {
+	message, err := NewBuilder(s.privateKey).
+		WithPagination(fmt.Sprintf("T%d", counter)).
+		WithMeterSerial(meterSerial).
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: float64(counter),
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+	return message.String()
+}
+
+func (s *batchTestSuite) TestVerifyAll() {
+	valid := s.buildMessage("exampleSerial123", 1)
+	tampered := strings.Replace(s.buildMessage("exampleSerial123", 2), `"RV":2`, `"RV":3`, 1)
+	unknownMeter := s.buildMessage("unknownSerial", 3)
+
+	// Signed, but with an invalid pagination
+	signature := NewDefaultSignature()
+	rawPayload := []byte(`{"FV":"1.0","PG":"4","MS":"exampleSerial123","IT":"NONE"}`)
+	s.Require().NoError(signature.SignBytes(rawPayload, s.privateKey))
+	invalid := "OCMF|" + string(rawPayload) + `|{"SD":"` + signature.Data + `"}`
+
+	messages := []string{valid, tampered, "OCMF|{}", unknownMeter, invalid, valid}
+
+	results, stats, err := NewBatchVerifier(s.registry).VerifyAll(context.Background(), messages)
+	s.Require().NoError(err)
+	s.Require().Len(results, len(messages))
+
+	expected := []BatchStatus{BatchStatusVerified, BatchStatusFailed, BatchStatusInvalid, BatchStatusError, BatchStatusVerified, BatchStatusVerified}
+	for i, result := range results {
+		s.Equal(i, result.Index)
+		s.Equal(expected[i], result.Status, "message %d", i)
+	}
+
+	s.NoError(results[0].Err)
+	s.Equal("exampleSerial123", results[0].Message.Payload.MeterSerial)
+	s.ErrorIs(results[1].Err, ErrVerificationFailure)
+	s.ErrorIs(results[2].Err, ErrInvalidFormat)
+	s.Nil(results[2].Message)
+	s.ErrorIs(results[3].Err, ErrKeyNotFound)
+
+	s.Equal(BatchStats{Total: 6, Verified: 3, Failed: 1, Invalid: 1, Errors: 1, Duration: stats.Duration}, stats)
+	s.Positive(stats.MessagesPerSecond())
+
+	// The changed pagination is only detected by the validation
+	results, stats, err = NewBatchVerifier(s.registry, WithBatchValidation()).VerifyAll(context.Background(), messages)
+	s.Require().NoError(err)
+	s.Equal(BatchStatusInvalid, results[4].Status)
+	s.Equal(2, stats.Invalid)
+}
+
+func (s *batchTestSuite) TestRun_order() {
+	// Resolve keys with random delays, so that the messages finish out of order
+	resolver := KeyResolverFunc(func(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error) {
+		time.Sleep(time.Duration(mathrand.Intn(500)) * time.Microsecond)
+		return s.registry.ResolvePublicKey(ctx, meterSerial)
+	})
+
+	message := s.buildMessage("exampleSerial123", 1)
+	input := make(chan string)
+	go func() {
+		defer close(input)
+
+		for i := 0; i < 200; i++ {
+			input <- message
+		}
+	}()
+
+	next := 0
+	stats, err := NewBatchVerifier(resolver, WithWorkers(8)).Run(context.Background(), input, func(result BatchResult) error {
+		s.Equal(next, result.Index)
+		s.Equal(BatchStatusVerified, result.Status)
+		next++
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Equal(200, next)
+	s.Equal(200, stats.Total)
+	s.Equal(200, stats.Verified)
+}
+
+func (s *batchTestSuite) TestRun_cancel() {
+	message := s.buildMessage("exampleSerial123", 1)
+
+	// An endless stream, only stopped by the context
+	input := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case input <- message:
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	emitted := 0
+	stats, err := NewBatchVerifier(s.registry, WithWorkers(4)).Run(ctx, input, func(result BatchResult) error {
+		emitted++
+		if emitted == 5 {
+			cancel()
+		}
+
+		return nil
+	})
+	s.ErrorIs(err, context.Canceled)
+	s.Equal(5, emitted)
+	s.Equal(5, stats.Total)
+}
+
+func (s *batchTestSuite) TestRun_emitError() {
+	errStop := errors.New("stop")
+	message := s.buildMessage("exampleSerial123", 1)
+
+	emitted := 0
+	_, err := NewBatchVerifier(s.registry, WithWorkers(2)).Run(context.Background(), s.stream(message, 50), func(result BatchResult) error {
+		emitted++
+		if emitted == 3 {
+			return errStop
+		}
+
+		return nil
+	})
+	s.ErrorIs(err, errStop)
+	s.Equal(3, emitted)
+}
+
+func (s *batchTestSuite) TestRun_noResolver() {
+	_, err := NewBatchVerifier(nil).Run(context.Background(), s.stream("", 1), func(result BatchResult) error {
+		return nil
+	})
+	s.Error(err)
+}
+
+func (s *batchTestSuite) stream(message string, count int) <-chan string {
+	input := make(chan string, count)
+	for i := 0; i < count; i++ {
+		input <- message
+	}
+
+	close(input)
+	return input
+}
+
+func TestBatchStats_MessagesPerSecond(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    BatchStats
+		expected float64
+	}{
+		{
+			name:     "Empty run",
+			stats:    BatchStats{},
+			expected: 0,
+		},
+		{
+			name:     "Two seconds",
+			stats:    BatchStats{Total: 1000, Duration: 2 * time.Second},
+			expected: 500,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.stats.MessagesPerSecond())
+		})
+	}
+}
+
+func TestBatchVerifier(t *testing.T) {
+	suite.Run(t, new(batchTestSuite))
+}
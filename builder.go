@@ -1,28 +1,50 @@
 package ocmf_go
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
-	"fmt"
+	stderrors "errors"
+	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/pkg/errors"
-	"github.com/samber/lo"
 )
 
+// Builder assembles a single OCMF message. It is not safe for concurrent use;
+// use a Template to share the meter and gateway identity between goroutines or transactions.
 type Builder struct {
-	payload    PayloadSection
-	signature  Signature
-	privateKey *ecdsa.PrivateKey
+	payload           PayloadSection
+	signature         Signature
+	signer            Signer
+	paginationManager *PaginationManager
+	paginationKind    PaginationKind
+	hooks             hooks
+	// errs holds the errors of the builder options
+	errs []error
 }
 
+// NewBuilder creates a Builder. Configuration errors are not returned, but Build refuses to sign until they are fixed.
+// Use NewBuilderE to get the errors when creating the builder.
 func NewBuilder(privateKey *ecdsa.PrivateKey, opts ...BuilderOption) *Builder {
+	var signer Signer
+	if privateKey != nil {
+		signer = &privateKeySigner{privateKey: privateKey}
+	}
+
+	return NewBuilderWithSigner(signer, opts...)
+}
+
+// NewBuilderWithSigner creates a Builder signing the messages with the signer, e.g. a key held by an HSM.
+func NewBuilderWithSigner(signer Signer, opts ...BuilderOption) *Builder {
 	builder := &Builder{
 		payload: PayloadSection{
 			FormatVersion: OcmfVersion,
 		},
 		// Set default signature parameters
-		signature:  *NewDefaultSignature(),
-		privateKey: privateKey,
+		signature: *NewDefaultSignature(),
+		signer:    signer,
 	}
 
 	// Apply builder options
@@ -33,6 +55,42 @@ func NewBuilder(privateKey *ecdsa.PrivateKey, opts ...BuilderOption) *Builder {
 	return builder
 }
 
+// NewBuilderE creates a Builder and returns an error if any of the options is invalid
+// or the private key is missing or cannot be used with the selected signature algorithm.
+func NewBuilderE(privateKey *ecdsa.PrivateKey, opts ...BuilderOption) (*Builder, error) {
+	builder := NewBuilder(privateKey, opts...)
+	return builder, builder.Err()
+}
+
+// Err returns the configuration errors of the builder, if any.
+func (b *Builder) Err() error {
+	errs := slices.Clone(b.errs)
+
+	switch {
+	case b.signer == nil:
+		errs = append(errs, errors.New("signer is required"))
+	case b.signer.Public() == nil:
+		errs = append(errs, errors.New("signer has no public key"))
+	default:
+		err := checkKeyAlgorithm(b.signer.Public(), b.signature.Algorithm)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "private key cannot be used"))
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// WithPayload replaces the whole payload section, e.g. with one decoded from JSON.
+func (b *Builder) WithPayload(payload PayloadSection) *Builder {
+	if payload.FormatVersion == "" {
+		payload.FormatVersion = OcmfVersion
+	}
+
+	b.payload = payload
+	return b
+}
+
 func (b *Builder) WithGatewayID(gatewayID string) *Builder {
 	b.payload.GatewayID = gatewayID
 	return b
@@ -134,28 +192,95 @@ func (b *Builder) ClearPayloadSection() {
 	}
 }
 
-func (b *Builder) Build() (*string, error) {
-	// Validate payload
-	err := b.payload.Validate()
+// Build validates and signs the payload and returns an independent Message.
+func (b *Builder) Build() (*Message, error) {
+	return b.BuildContext(context.Background())
+}
+
+// BuildContext is like Build, but passes the context on to the signer.
+func (b *Builder) BuildContext(ctx context.Context) (*Message, error) {
+	start := time.Now()
+
+	message, reason, err := b.build(ctx)
+	if err != nil {
+		b.hooks.meter().BuildFailed(reason)
+		b.hooks.log(ctx, slog.LevelWarn, "failed to build OCMF message",
+			"meterSerial", b.payload.MeterSerial, "reason", reason, "error", err)
+		return nil, err
+	}
+
+	b.hooks.meter().MessageBuilt()
+	b.hooks.log(ctx, slog.LevelDebug, "OCMF message built",
+		"meterSerial", message.Payload.MeterSerial, "pagination", message.Payload.Pagination, "duration", time.Since(start))
+
+	return message, nil
+}
+
+// build returns the reason of the failure together with the error.
+func (b *Builder) build(ctx context.Context) (*Message, FailureReason, error) {
+	if err := b.Err(); err != nil {
+		return nil, ReasonConfiguration, errors.Wrap(err, "invalid builder configuration")
+	}
+
+	if b.paginationManager == nil || b.payload.Pagination != "" {
+		return b.sign(ctx, b.payload)
+	}
+
+	// The message is signed with the next counter, which is only committed after the signing succeeded.
+	// If another builder committed it in the meantime, the message is signed again with a new counter.
+	for {
+		counters, err := b.paginationManager.Current(b.payload.MeterSerial)
+		if err != nil {
+			return nil, ReasonPagination, errors.Wrap(err, "failed to load pagination counters")
+		}
+
+		pagination := nextPagination(counters, b.paginationKind)
+		payloadSection := b.payload
+		payloadSection.Pagination = pagination.String()
+
+		message, reason, err := b.sign(ctx, payloadSection)
+		if err != nil {
+			return nil, reason, err
+		}
+
+		err = b.paginationManager.Commit(payloadSection.MeterSerial, pagination)
+		switch {
+		case err == nil:
+			return message, "", nil
+		case !errors.Is(err, ErrPaginationConflict):
+			return nil, ReasonPagination, errors.Wrap(err, "failed to commit pagination")
+		}
+	}
+}
+
+// sign validates and signs the payload section.
+func (b *Builder) sign(ctx context.Context, payloadSection PayloadSection) (*Message, FailureReason, error) {
+	err := payloadSection.Validate()
 	if err != nil {
-		return nil, errors.Wrap(err, "payload validation failed")
+		return nil, ReasonValidation, errors.Wrap(err, "payload validation failed")
 	}
 
-	// Sign payload with private key
-	err = b.signature.Sign(b.payload, b.privateKey)
+	payload, err := json.Marshal(payloadSection)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to sign message")
+		return nil, ReasonError, errors.Wrap(err, "failed to marshal payload")
 	}
 
-	payload, err := json.Marshal(b.payload)
+	// Sign a copy, so the signature data does not leak into the next message
+	signatureSection := b.signature
+	err = signatureSection.SignBytesContext(ctx, payload, b.signer)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal payload")
+		return nil, ReasonSigning, errors.Wrap(err, "failed to sign message")
 	}
 
-	signature, err := json.Marshal(b.signature)
+	signature, err := json.Marshal(signatureSection)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal signature")
+		return nil, ReasonError, errors.Wrap(err, "failed to marshal signature")
 	}
 
-	return lo.ToPtr(fmt.Sprintf("OCMF|%s|%s", payload, signature)), nil
+	return &Message{
+		Payload:      payloadSection,
+		RawPayload:   payload,
+		Signature:    signatureSection,
+		RawSignature: signature,
+	}, "", nil
 }
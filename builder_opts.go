@@ -1,30 +1,76 @@
 package ocmf_go
 
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/pkg/errors"
+)
+
+// BuilderOption configures a Builder. Invalid options are not applied and their errors are reported by Builder.Err.
 type BuilderOption func(*Builder)
 
 func WithSignatureAlgorithm(algorithm SignatureAlgorithm) BuilderOption {
 	return func(b *Builder) {
-		if isValidSignatureAlgorithm(algorithm) {
-			b.signature.Algorithm = algorithm
+		if !isValidSignatureAlgorithm(algorithm) {
+			b.errs = append(b.errs, fmt.Errorf("invalid signature algorithm: %q", algorithm))
+			return
 		}
+
+		b.signature.Algorithm = algorithm
 	}
 }
 
 func WithSignatureEncoding(encoding SignatureEncoding) BuilderOption {
 	return func(b *Builder) {
-		if isValidSignatureEncoding(encoding) {
-			b.signature.Encoding = encoding
+		if !isValidSignatureEncoding(encoding) {
+			b.errs = append(b.errs, fmt.Errorf("invalid signature encoding: %q", encoding))
+			return
 		}
+
+		b.signature.Encoding = encoding
 	}
 }
 
+// WithSignature sets the signature parameters. The signature data is ignored, as it is set when the message is built.
 func WithSignature(signature Signature) BuilderOption {
 	return func(b *Builder) {
-		err := signature.Validate()
+		err := signatureValidator.StructExcept(signature, "Data")
 		if err != nil {
+			b.errs = append(b.errs, errors.Wrap(err, "invalid signature"))
 			return
 		}
 
+		signature.Data = ""
 		b.signature = signature
 	}
 }
+
+// WithPaginationManager draws the PG value from the manager on every Build, unless a pagination was set explicitly.
+func WithPaginationManager(manager *PaginationManager, kind PaginationKind) BuilderOption {
+	return func(b *Builder) {
+		switch {
+		case manager == nil:
+			b.errs = append(b.errs, errors.New("pagination manager is required"))
+		case !isValidPaginationKind(kind):
+			b.errs = append(b.errs, fmt.Errorf("invalid pagination kind: %q", kind))
+		default:
+			b.paginationManager = manager
+			b.paginationKind = kind
+		}
+	}
+}
+
+// WithBuilderLogger logs built messages as debug messages and failed builds as warnings.
+func WithBuilderLogger(logger *slog.Logger) BuilderOption {
+	return func(b *Builder) {
+		b.hooks.logger = logger
+	}
+}
+
+// WithBuilderMetrics reports the built messages and failed builds to the metrics.
+func WithBuilderMetrics(metrics Metrics) BuilderOption {
+	return func(b *Builder) {
+		b.hooks.metrics = metrics
+	}
+}
@@ -113,6 +113,115 @@ func (s *builderOptsTestSuite) TestWithWithSignatureEncoding() {
 		})
 	}
 }
+
+func (s *builderOptsTestSuite) TestWithSignature() {
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	s.Require().NoError(err)
+
+	builder, err := NewBuilderE(p384Key, WithSignature(Signature{
+		Algorithm: SignatureAlgorithmECDSAsecp384r1SHA256,
+		Encoding:  SignatureEncodingBase64,
+		MimeType:  SignatureMimeTypeDer,
+		Data:      "ignored",
+	}))
+	s.Require().NoError(err)
+	s.Equal(SignatureAlgorithmECDSAsecp384r1SHA256, builder.signature.Algorithm)
+	s.Equal(SignatureEncodingBase64, builder.signature.Encoding)
+	s.Empty(builder.signature.Data)
+
+	builder, err = NewBuilderE(p384Key, WithSignature(Signature{Algorithm: "ABCD"}))
+	s.ErrorContains(err, "invalid signature")
+	s.Equal(*NewDefaultSignature(), builder.signature)
+}
+
+func (s *builderOptsTestSuite) TestNewBuilderE() {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	s.Require().NoError(err)
+
+	tests := []struct {
+		name       string
+		privateKey *ecdsa.PrivateKey
+		opts       []BuilderOption
+		error      error
+		errorText  []string
+	}{
+		{
+			name:       "Default options",
+			privateKey: p256Key,
+		},
+		{
+			name:       "Without private key",
+			privateKey: nil,
+			errorText:  []string{"signer is required"},
+		},
+		{
+			name:       "P384 key with secp384r1",
+			privateKey: p384Key,
+			opts:       []BuilderOption{WithSignatureAlgorithm(SignatureAlgorithmECDSAsecp384r1SHA256)},
+		},
+		{
+			name:       "P384 key with default algorithm",
+			privateKey: p384Key,
+			error:      ErrKeyAlgorithmMismatch,
+		},
+		{
+			name:       "Algorithm without curve support",
+			privateKey: p256Key,
+			opts:       []BuilderOption{WithSignatureAlgorithm(SignatureAlgorithmECDSAsecp256k1SHA256)},
+			error:      ErrUnsupportedAlgorithm,
+		},
+		{
+			name:       "Multiple invalid options",
+			privateKey: p256Key,
+			opts: []BuilderOption{
+				WithSignatureAlgorithm("ABCD"),
+				WithSignatureEncoding("ABCD"),
+				WithPaginationManager(nil, PaginationTransaction),
+			},
+			errorText: []string{"invalid signature algorithm", "invalid signature encoding", "pagination manager is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			builder, err := NewBuilderE(tt.privateKey, tt.opts...)
+			s.NotNil(builder)
+
+			switch {
+			case tt.error != nil:
+				s.ErrorIs(err, tt.error)
+			case len(tt.errorText) > 0:
+				for _, text := range tt.errorText {
+					s.ErrorContains(err, text)
+				}
+			default:
+				s.NoError(err)
+				return
+			}
+
+			// The builder refuses to sign with an invalid configuration
+			_, err = builder.
+				WithPagination("T1").
+				WithMeterSerial("exampleSerial123").
+				WithIdentificationType(string(RfidNone)).
+				AddReading(Reading{
+					Time:         "2018-07-24T13:22:04,000+0200 S",
+					ReadingValue: 1,
+					ReadingUnit:  string(UnitskWh),
+					Status:       string(MeterOk),
+				}).
+				Build()
+			s.ErrorContains(err, "invalid builder configuration")
+
+			// Templates keep the configuration errors
+			_, err = builder.Template().NewBuilder().Build()
+			s.ErrorContains(err, "invalid builder configuration")
+		})
+	}
+}
+
 func TestBuilderOpts(t *testing.T) {
 	suite.Run(t, new(builderOptsTestSuite))
 }
@@ -34,7 +34,7 @@ func (s *builderTestSuite) TestBuilder_Valid() {
 	s.Require().NoError(err)
 
 	builder := NewBuilder(privateKey).
-		WithPagination("1").
+		WithPagination("T1").
 		WithMeterSerial("exampleSerial123").
 		WithIdentificationStatus(true).
 		WithIdentificationType(string(RfidNone)).
@@ -45,7 +45,7 @@ func (s *builderTestSuite) TestBuilder_Valid() {
 			Status:       string(MeterOk),
 		})
 
-	s.Equal("1", builder.payload.Pagination)
+	s.Equal("T1", builder.payload.Pagination)
 	s.Equal("exampleSerial123", builder.payload.MeterSerial)
 	s.Equal(true, builder.payload.IdentificationStatus)
 	s.Equal(string(RfidNone), builder.payload.IdentificationType)
@@ -62,7 +62,7 @@ func (s *builderTestSuite) TestBuilder_Valid() {
 
 func (s *builderTestSuite) TestBuilder_MissingAttributes() {
 	builder := NewBuilder(nil).
-		// WithPagination("1").
+		// WithPagination("T1").
 		WithMeterSerial("exampleSerial123").
 		WithIdentificationStatus(true).
 		WithIdentificationType(string(RfidNone)).
@@ -80,7 +80,7 @@ func (s *builderTestSuite) TestBuilder_MissingAttributes() {
 
 func (s *builderTestSuite) TestBuilder_CantSign() {
 	builder := NewBuilder(nil).
-		WithPagination("1").
+		WithPagination("T1").
 		WithMeterSerial("exampleSerial123").
 		WithIdentificationStatus(true).
 		WithIdentificationType(string(RfidNone)).
@@ -96,6 +96,50 @@ func (s *builderTestSuite) TestBuilder_CantSign() {
 	s.Nil(payload)
 }
 
+func (s *builderTestSuite) TestBuilder_DoesNotKeepSignatureData() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	builder := NewBuilder(privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 123,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		})
+
+	_, err = builder.Build()
+	s.Require().NoError(err)
+	s.Empty(builder.signature.Data)
+}
+
+func (s *builderTestSuite) TestBuilder_WithPayload() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	builder := NewBuilder(privateKey).WithPayload(PayloadSection{
+		Pagination:         "T1",
+		MeterSerial:        "exampleSerial123",
+		IdentificationType: string(RfidNone),
+		Readings: []Reading{
+			{
+				Time:         "2018-07-24T13:22:04,000+0200 S",
+				ReadingValue: 123,
+				ReadingUnit:  string(UnitskWh),
+				Status:       string(MeterOk),
+			},
+		},
+	})
+	s.Equal(OcmfVersion, builder.payload.FormatVersion)
+
+	message, err := builder.Build()
+	s.Require().NoError(err)
+	s.Equal("exampleSerial123", message.Payload.MeterSerial)
+}
+
 func TestBuilder(t *testing.T) {
 	suite.Run(t, new(builderTestSuite))
 }
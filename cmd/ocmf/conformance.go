@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChargePi/ocmf-go/conformance"
+	"github.com/spf13/cobra"
+)
+
+func newConformanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conformance [dir]",
+		Short: "Run conformance vectors against the library",
+		Long: `Run conformance vectors against the library.
+
+Without a directory, the built-in corpus is used. See the conformance package for the vector format.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			corpus := conformance.Corpus()
+			if len(args) > 0 {
+				corpus = os.DirFS(args[0])
+			}
+
+			results, err := conformance.RunCorpus(corpus)
+			if err != nil {
+				return usageError(err)
+			}
+
+			asJSON, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				return usageError(err)
+			}
+
+			failed := 0
+			for _, result := range results {
+				if !result.Passed() {
+					failed++
+				}
+			}
+
+			if asJSON {
+				if err := writeJSON(cmd, results); err != nil {
+					return err
+				}
+			} else {
+				out := cmd.OutOrStdout()
+				for _, result := range results {
+					if result.Passed() {
+						_, _ = fmt.Fprintf(out, "%s: OK\n", result.File)
+						continue
+					}
+
+					_, _ = fmt.Fprintf(out, "%s: FAILED\n", result.File)
+					for _, failure := range result.Failures {
+						_, _ = fmt.Fprintf(out, "  %s\n", failure)
+					}
+				}
+
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d vector(s), %d failed\n", len(results), failed)
+			}
+
+			if failed > 0 {
+				return &exitError{code: exitFailure, err: errFailedMessages}
+			}
+
+			return nil
+		},
+	}
+
+	addOutputFlags(cmd)
+	return cmd
+}
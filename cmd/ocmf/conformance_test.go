@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type conformanceTestSuite struct {
+	suite.Suite
+}
+
+func (s *conformanceTestSuite) TestBuiltinCorpus() {
+	code, stdout, stderr := execute("", "conformance")
+	s.Equal(0, code)
+	s.Contains(stdout, "vectors/01-full-payload-hex.json: OK\n")
+	s.Contains(stderr, "19 vector(s), 0 failed")
+}
+
+func (s *conformanceTestSuite) TestCustomCorpus() {
+	dir := s.T().TempDir()
+	vector := `{"name":"Wrong expectation","message":"OCMF|{}|{}","expect":{"parse":false}}`
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "vector.json"), []byte(vector), 0o600))
+
+	code, stdout, stderr := execute("", "conformance", dir)
+	s.Equal(exitFailure, code)
+	s.Contains(stdout, "vector.json: FAILED\n  parse: expected false, got error <nil>\n")
+	s.Contains(stderr, "1 vector(s), 1 failed")
+
+	code, _, stderr = execute("", "conformance", filepath.Join(dir, "missing"))
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "no such file or directory")
+}
+
+func TestConformanceCmd(t *testing.T) {
+	suite.Run(t, new(conformanceTestSuite))
+}
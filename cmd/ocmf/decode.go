@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/spf13/cobra"
+)
+
+type decodedMessage struct {
+	Source    string               `json:"source"`
+	Payload   *ocmf.PayloadSection `json:"payload,omitempty"`
+	Signature *ocmf.Signature      `json:"signature,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+func newDecodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode [message...]",
+		Short: "Decode messages into pretty printed JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs, err := readInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			failed := false
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+
+			for _, in := range inputs {
+				decoded := decodedMessage{Source: in.source}
+
+				message, err := ocmf.ParseMessage(in.data)
+				if err != nil {
+					failed = true
+					decoded.Error = err.Error()
+				} else {
+					decoded.Payload = &message.Payload
+					decoded.Signature = &message.Signature
+				}
+
+				if err := encoder.Encode(decoded); err != nil {
+					return usageError(fmt.Errorf("failed to write output: %w", err))
+				}
+			}
+
+			if failed {
+				return &exitError{code: exitFailure, err: errFailedMessages}
+			}
+
+			return nil
+		},
+	}
+
+	addInputFlags(cmd)
+	return cmd
+}
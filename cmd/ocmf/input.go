@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// maxMessageSize is the longest line accepted from files and stdin.
+const maxMessageSize = 1024 * 1024
+
+type input struct {
+	// source identifies the message in the output, e.g. "stdin:3" or "arg:1"
+	source string
+	data   string
+}
+
+func addInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("file", "f", nil, "read messages from the file, one message per line (repeatable, - for stdin)")
+}
+
+// readInputs collects the messages from the arguments, the files or stdin, in this order of precedence.
+func readInputs(cmd *cobra.Command, args []string) ([]input, error) {
+	files, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return nil, usageError(err)
+	}
+
+	inputs := []input{}
+	for i, arg := range args {
+		if arg == "-" {
+			files = append(files, "-")
+			continue
+		}
+
+		inputs = append(inputs, input{source: fmt.Sprintf("arg:%d", i+1), data: arg})
+	}
+
+	if len(inputs) == 0 && len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	for _, file := range files {
+		fileInputs, err := readFile(cmd, file)
+		if err != nil {
+			return nil, usageError(err)
+		}
+
+		inputs = append(inputs, fileInputs...)
+	}
+
+	if len(inputs) == 0 {
+		return nil, usageError(errors.New("no messages to process"))
+	}
+
+	return inputs, nil
+}
+
+func readFile(cmd *cobra.Command, file string) ([]input, error) {
+	var reader io.Reader
+	name := file
+
+	if file == "-" {
+		reader = cmd.InOrStdin()
+		name = "stdin"
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open file")
+		}
+		defer f.Close()
+
+		reader = f
+	}
+
+	return readLines(reader, name)
+}
+
+func readLines(reader io.Reader, name string) ([]input, error) {
+	inputs := []input{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		data := strings.TrimSpace(scanner.Text())
+		if data == "" || strings.HasPrefix(data, "#") {
+			continue
+		}
+
+		inputs = append(inputs, input{source: fmt.Sprintf("%s:%d", name, line), data: data})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", name)
+	}
+
+	return inputs, nil
+}
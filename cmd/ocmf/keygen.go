@@ -0,0 +1,130 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type generatedKey struct {
+	Algorithm     ocmf.SignatureAlgorithm `json:"algorithm"`
+	PrivateKeyPEM string                  `json:"privateKey"`
+	PublicKeyPEM  string                  `json:"publicKey"`
+	// PublicKeyHex is the hex encoded DER key, as printed on meter labels and used by the transparency software
+	PublicKeyHex string `json:"publicKeyHex"`
+	// PublicKeyRawHex is the hex encoded uncompressed curve point
+	PublicKeyRawHex string `json:"publicKeyRawHex"`
+}
+
+func newKeygenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a meter key pair for a signature algorithm",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			algorithm, err := cmd.Flags().GetString("algorithm")
+			if err != nil {
+				return usageError(err)
+			}
+
+			out, err := cmd.Flags().GetString("out")
+			if err != nil {
+				return usageError(err)
+			}
+
+			privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithm(algorithm))
+			if err != nil {
+				return usageError(err)
+			}
+
+			key, err := encodeKey(ocmf.SignatureAlgorithm(algorithm), privateKey)
+			if err != nil {
+				return usageError(err)
+			}
+
+			if out != "" {
+				return writeKeyFiles(cmd, out, key)
+			}
+
+			asJSON, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				return usageError(err)
+			}
+
+			if asJSON {
+				return writeJSON(cmd, key)
+			}
+
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), key.PrivateKeyPEM, key.PublicKeyPEM)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Public key (hex): %s\nPublic key (raw hex): %s\n", key.PublicKeyHex, key.PublicKeyRawHex)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("algorithm", "a", string(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256), "signature algorithm the key is used with")
+	cmd.Flags().StringP("out", "o", "", "write the keys to <out>.key, <out>.pub and <out>.pub.hex instead of stdout")
+	addOutputFlags(cmd)
+	return cmd
+}
+
+func encodeKey(algorithm ocmf.SignatureAlgorithm, privateKey *ecdsa.PrivateKey) (*generatedKey, error) {
+	privateDer, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal private key")
+	}
+
+	publicDer, err := ocmf.MarshalPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	point, err := privateKey.PublicKey.ECDH()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode public key")
+	}
+
+	return &generatedKey{
+		Algorithm:       algorithm,
+		PrivateKeyPEM:   string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateDer})),
+		PublicKeyPEM:    string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})),
+		PublicKeyHex:    strings.ToUpper(hex.EncodeToString(publicDer)),
+		PublicKeyRawHex: strings.ToUpper(hex.EncodeToString(point.Bytes())),
+	}, nil
+}
+
+func writeKeyFiles(cmd *cobra.Command, out string, key *generatedKey) error {
+	files := []struct {
+		path    string
+		content string
+		mode    os.FileMode
+	}{
+		{path: out + ".key", content: key.PrivateKeyPEM, mode: 0o600},
+		{path: out + ".pub", content: key.PublicKeyPEM, mode: 0o644},
+		{path: out + ".pub.hex", content: key.PublicKeyHex + "\n", mode: 0o644},
+	}
+
+	for _, file := range files {
+		// Never overwrite an existing key
+		f, err := os.OpenFile(file.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, file.mode)
+		if err != nil {
+			return usageError(errors.Wrap(err, "failed to create key file"))
+		}
+
+		_, err = f.WriteString(file.content)
+		closeErr := f.Close()
+		if err != nil || closeErr != nil {
+			return usageError(errors.Errorf("failed to write %s", file.path))
+		}
+
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Wrote", file.path)
+	}
+
+	return nil
+}
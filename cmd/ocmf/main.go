@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// exitFailure is returned if at least one message could not be parsed, validated or verified.
+	exitFailure = 1
+	// exitUsage is returned for invalid arguments and I/O errors.
+	exitUsage = 2
+)
+
+// exitError carries the exit code of a failed command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func usageError(err error) error {
+	return &exitError{code: exitUsage, err: err}
+}
+
+var errFailedMessages = errors.New("one or more messages failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs(args)
+	rootCmd.SetIn(stdin)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+
+	err := rootCmd.Execute()
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		if !errors.Is(exitErr.err, errFailedMessages) {
+			_, _ = fmt.Fprintln(stderr, "Error:", exitErr.err)
+		}
+
+		return exitErr.code
+	}
+
+	// Cobra argument and flag errors
+	_, _ = fmt.Fprintln(stderr, "Error:", err)
+	return exitUsage
+}
+
+func newRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "ocmf",
+		Short: "Parse, validate, verify and sign Open Charge Metering Format messages",
+		Long: `Parse, validate and verify Open Charge Metering Format (OCMF) messages.
+
+The decode, validate and verify commands read messages from the arguments, from files (one message per line)
+or from stdin. The exit code is 0 if all messages passed, 1 if at least one failed and 2 on usage or I/O errors.
+
+For testing and integration, keygen generates meter keys, sign signs payload sections, serve runs the
+verification service, schema prints the JSON schemas of the sections, simulate runs a virtual meter and
+conformance runs a corpus of messages with their expected results.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	rootCmd.AddCommand(
+		newDecodeCmd(),
+		newValidateCmd(),
+		newVerifyCmd(),
+		newKeygenCmd(),
+		newSignCmd(),
+		newServeCmd(),
+		newSchemaCmd(),
+		newSimulateCmd(),
+		newConformanceCmd(),
+	)
+
+	return rootCmd
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type cliTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	message    string
+}
+
+func (s *cliTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	der, err := ocmf.MarshalPublicKey(&privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.publicKey = hex.EncodeToString(der)
+
+	message, err := ocmf.NewBuilder(privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(ocmf.RfidNone)).
+		AddReading(ocmf.Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 10,
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+	s.message = message.String()
+}
+
+func execute(stdin string, args ...string) (int, string, string) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func (s *cliTestSuite) TestDecode() {
+	code, stdout, _ := execute("", "decode", s.message)
+	s.Equal(0, code)
+
+	decoded := decodedMessage{}
+	s.Require().NoError(json.Unmarshal([]byte(stdout), &decoded))
+	s.Equal("arg:1", decoded.Source)
+	s.Equal("exampleSerial123", decoded.Payload.MeterSerial)
+	s.NotEmpty(decoded.Signature.Data)
+
+	code, stdout, _ = execute("", "decode", "OCMF|{}")
+	s.Equal(exitFailure, code)
+	s.Contains(stdout, `"error": "invalid OCMF message format"`)
+}
+
+func (s *cliTestSuite) TestValidate() {
+	invalid := strings.Replace(s.message, `"PG":"T1"`, `"PG":"1"`, 1)
+
+	code, stdout, stderr := execute("", "validate", s.message, invalid)
+	s.Equal(exitFailure, code)
+	s.Contains(stdout, "arg:1: OK\n")
+	s.Contains(stdout, "arg:2: FAILED: validation failed\n")
+	s.Contains(stdout, `  PG: PG has an invalid value "1" for rule 'pagination'`)
+	s.Contains(stderr, "2 message(s), 1 failed")
+
+	code, stdout, _ = execute("", "validate", "--json", invalid)
+	s.Equal(exitFailure, code)
+
+	results := []result{}
+	s.Require().NoError(json.Unmarshal([]byte(stdout), &results))
+	s.Require().Len(results, 1)
+	s.False(results[0].Valid)
+	s.Equal("PG", results[0].Diagnostics[0].Field)
+}
+
+func (s *cliTestSuite) TestVerify() {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	otherDer, err := ocmf.MarshalPublicKey(&otherKey.PublicKey)
+	s.Require().NoError(err)
+
+	code, stdout, _ := execute("", "verify", "--key", s.publicKey, s.message)
+	s.Equal(0, code)
+	s.Equal("arg:1: OK\n", stdout)
+
+	code, stdout, _ = execute("", "verify", "--key", hex.EncodeToString(otherDer), s.message)
+	s.Equal(exitFailure, code)
+	s.Contains(stdout, "arg:1: FAILED: verification failed")
+
+	keyFile := filepath.Join(s.T().TempDir(), "key.hex")
+	s.Require().NoError(os.WriteFile(keyFile, []byte(s.publicKey+"\n"), 0o600))
+
+	code, stdout, _ = execute("", "verify", "--key-file", keyFile, "--json", s.message)
+	s.Equal(0, code)
+
+	results := []result{}
+	s.Require().NoError(json.Unmarshal([]byte(stdout), &results))
+	s.Require().Len(results, 1)
+	s.True(results[0].Valid)
+	s.Equal("exampleSerial123", results[0].MeterSerial)
+	s.Equal("T1", results[0].Pagination)
+}
+
+func (s *cliTestSuite) TestVerify_usage() {
+	code, _, stderr := execute("", "verify", s.message)
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "Error:")
+
+	code, _, stderr = execute("", "verify", "--key", "invalid", s.message)
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "invalid public key")
+
+	code, _, _ = execute("", "verify", "--key", s.publicKey, "--file", "does-not-exist.txt")
+	s.Equal(exitUsage, code)
+
+	code, _, stderr = execute("", "validate")
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "no messages to process")
+}
+
+func (s *cliTestSuite) TestBulkInput() {
+	file := filepath.Join(s.T().TempDir(), "messages.txt")
+	content := "# exported messages\n" + s.message + "\n\n" + s.message + "\n"
+	s.Require().NoError(os.WriteFile(file, []byte(content), 0o600))
+
+	code, stdout, stderr := execute("", "verify", "--key", s.publicKey, "--file", file)
+	s.Equal(0, code)
+	s.Contains(stdout, "messages.txt:2: OK\n")
+	s.Contains(stdout, "messages.txt:4: OK\n")
+	s.Contains(stderr, "2 message(s), 0 failed")
+
+	code, stdout, _ = execute(s.message+"\nOCMF|{}\n", "validate")
+	s.Equal(exitFailure, code)
+	s.Contains(stdout, "stdin:1: OK\n")
+	s.Contains(stdout, "stdin:2: FAILED: invalid OCMF message format\n")
+}
+
+func TestCLI(t *testing.T) {
+	suite.Run(t, new(cliTestSuite))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/spf13/cobra"
+)
+
+// result is the outcome of validating or verifying a single message.
+type result struct {
+	Source      string            `json:"source"`
+	Valid       bool              `json:"valid"`
+	MeterSerial string            `json:"meterSerial,omitempty"`
+	Pagination  string            `json:"pagination,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Diagnostics []ocmf.FieldError `json:"diagnostics,omitempty"`
+}
+
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "print the results as JSON")
+}
+
+func writeJSON(cmd *cobra.Command, value any) error {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return usageError(fmt.Errorf("failed to write output: %w", err))
+	}
+
+	return nil
+}
+
+// printResults prints one line per message, followed by its diagnostics, or all results as a JSON array.
+// It returns an error with exitFailure if any of the results is not valid.
+func printResults(cmd *cobra.Command, results []result) error {
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return usageError(err)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Valid {
+			failed++
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if asJSON {
+		if err := writeJSON(cmd, results); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range results {
+			if result.Valid {
+				_, _ = fmt.Fprintf(out, "%s: OK\n", result.Source)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(out, "%s: FAILED: %s\n", result.Source, result.Error)
+			for _, diagnostic := range result.Diagnostics {
+				_, _ = fmt.Fprintf(out, "  %s: %s\n", diagnostic.Field, diagnostic.Message)
+			}
+		}
+
+		if len(results) > 1 {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%d message(s), %d failed\n", len(results), failed)
+		}
+	}
+
+	if failed > 0 {
+		return &exitError{code: exitFailure, err: errFailedMessages}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var schemaDefinitions = []ocmf.SchemaDefinition{
+	ocmf.SchemaPayloadSection,
+	ocmf.SchemaReading,
+	ocmf.SchemaLossCompensation,
+	ocmf.SchemaSignature,
+}
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "schema [PayloadSection|Reading|LossCompensation|Signature]",
+		Short:     "Print the JSON schema of a message section",
+		Long:      "Print the JSON schema of a message section, derived from the validation rules of the library.",
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"PayloadSection", "Reading", "LossCompensation", "Signature"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			formatVersion, err := cmd.Flags().GetString("format-version")
+			if err != nil {
+				return usageError(err)
+			}
+
+			out, err := cmd.Flags().GetString("out")
+			if err != nil {
+				return usageError(err)
+			}
+
+			if out != "" {
+				return writeSchemaFiles(cmd, out, formatVersion)
+			}
+
+			definition := ocmf.SchemaPayloadSection
+			if len(args) > 0 {
+				definition = ocmf.SchemaDefinition(args[0])
+			}
+
+			schema, err := ocmf.GenerateJSONSchema(formatVersion, definition)
+			if err != nil {
+				return usageError(err)
+			}
+
+			return writeJSON(cmd, schema)
+		},
+	}
+
+	cmd.Flags().String("format-version", ocmf.OcmfVersion, "OCMF format version of the schema")
+	cmd.Flags().String("out", "", "write the schemas of all sections to <out>/<format version>/<section>.json")
+	return cmd
+}
+
+func writeSchemaFiles(cmd *cobra.Command, out, formatVersion string) error {
+	dir := filepath.Join(out, formatVersion)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return usageError(errors.Wrap(err, "failed to create schema directory"))
+	}
+
+	for _, definition := range schemaDefinitions {
+		schema, err := ocmf.GenerateJSONSchema(formatVersion, definition)
+		if err != nil {
+			return usageError(err)
+		}
+
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return usageError(errors.Wrap(err, "failed to encode schema"))
+		}
+
+		path := filepath.Join(dir, string(definition)+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return usageError(errors.Wrap(err, "failed to write schema"))
+		}
+
+		cmd.PrintErrf("Wrote %s\n", path)
+	}
+
+	return nil
+}
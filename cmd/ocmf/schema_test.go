@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type schemaTestSuite struct {
+	suite.Suite
+}
+
+func (s *schemaTestSuite) TestSchema() {
+	code, stdout, _ := execute("", "schema")
+	s.Equal(0, code)
+
+	schema := ocmf.JSONSchema{}
+	s.Require().NoError(json.Unmarshal([]byte(stdout), &schema))
+	s.Equal("OCMF 0.4 PayloadSection", schema.Title)
+
+	code, stdout, _ = execute("", "schema", "Signature")
+	s.Equal(0, code)
+	s.Contains(stdout, `"title": "OCMF 0.4 Signature"`)
+
+	code, _, stderr := execute("", "schema", "Message")
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "invalid argument")
+
+	code, _, stderr = execute("", "schema", "--format-version", "0.1")
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "unsupported format version")
+}
+
+func (s *schemaTestSuite) TestSchemaOut() {
+	dir := s.T().TempDir()
+
+	code, _, stderr := execute("", "schema", "--out", dir)
+	s.Equal(0, code)
+	s.Contains(stderr, "Wrote ")
+
+	for _, definition := range schemaDefinitions {
+		data, err := os.ReadFile(filepath.Join(dir, ocmf.OcmfVersion, string(definition)+".json"))
+		s.Require().NoError(err)
+		s.True(json.Valid(data))
+	}
+}
+
+func TestSchemaCmd(t *testing.T) {
+	suite.Run(t, new(schemaTestSuite))
+}
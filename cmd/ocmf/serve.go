@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/ChargePi/ocmf-go/server"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a JSON API to parse, validate and verify messages",
+		Long: `Serve a JSON API to parse, validate and verify messages.
+
+The keys file maps meter serials to their public keys, as JSON or YAML:
+
+  meter-serial-1: 3059301306072a8648ce3d0201...
+
+Without a keys file, messages can only be verified with a key sent in the request, and only if
+--trust-request-keys is set. Such a verification does not prove that the key belongs to the meter.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := cmd.Flags().GetString("addr")
+			if err != nil {
+				return usageError(err)
+			}
+
+			keysFile, err := cmd.Flags().GetString("keys")
+			if err != nil {
+				return usageError(err)
+			}
+
+			maxRequestSize, err := cmd.Flags().GetInt64("max-request-size")
+			if err != nil {
+				return usageError(err)
+			}
+
+			timeout, err := cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return usageError(err)
+			}
+
+			trustRequestKeys, err := cmd.Flags().GetBool("trust-request-keys")
+			if err != nil {
+				return usageError(err)
+			}
+
+			var resolver ocmf.KeyResolver
+			if keysFile != "" {
+				file, err := os.Open(keysFile)
+				if err != nil {
+					return usageError(errors.Wrap(err, "failed to open keys file"))
+				}
+				defer file.Close()
+
+				registry, err := server.LoadKeyRegistry(file)
+				if err != nil {
+					return usageError(err)
+				}
+				resolver = registry
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			cmd.PrintErrf("Listening on %s\n", addr)
+			opts := []server.Option{server.WithMaxRequestSize(maxRequestSize), server.WithRequestTimeout(timeout)}
+			if trustRequestKeys {
+				opts = append(opts, server.WithTrustRequestKeys())
+			}
+
+			srv := server.New(resolver, opts...)
+			if err := srv.ListenAndServe(ctx, addr); err != nil {
+				return &exitError{code: exitFailure, err: err}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("addr", ":8080", "address to listen on")
+	cmd.Flags().String("keys", "", "JSON or YAML file mapping meter serials to public keys")
+	cmd.Flags().Int64("max-request-size", server.DefaultMaxRequestSize, "maximum request body size in bytes")
+	cmd.Flags().Duration("timeout", server.DefaultRequestTimeout, "maximum duration of a request")
+	cmd.Flags().Bool("trust-request-keys", false, "verify with public keys sent in requests if no keys file is given")
+	return cmd
+}
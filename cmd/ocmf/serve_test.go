@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type serveTestSuite struct {
+	suite.Suite
+}
+
+func (s *serveTestSuite) TestServeInvalidKeys() {
+	code, _, stderr := execute("", "serve", "--keys", filepath.Join(s.T().TempDir(), "missing.yaml"))
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "failed to open keys file")
+
+	keysFile := filepath.Join(s.T().TempDir(), "keys.yaml")
+	s.Require().NoError(os.WriteFile(keysFile, []byte("meter1: abc\n"), 0o600))
+
+	code, _, stderr = execute("", "serve", "--keys", keysFile)
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "meter meter1")
+}
+
+func TestServe(t *testing.T) {
+	suite.Run(t, new(serveTestSuite))
+}
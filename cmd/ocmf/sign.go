@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign a payload section and print the OCMF message",
+		Long: `Sign a payload section and print the OCMF message.
+
+The payload is a PayloadSection in JSON or YAML, using the OCMF field names (PG, MS, RD, ...).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyFile, err := cmd.Flags().GetString("key-file")
+			if err != nil {
+				return usageError(err)
+			}
+
+			payloadFile, err := cmd.Flags().GetString("payload")
+			if err != nil {
+				return usageError(err)
+			}
+
+			algorithm, err := cmd.Flags().GetString("algorithm")
+			if err != nil {
+				return usageError(err)
+			}
+
+			encoding, err := cmd.Flags().GetString("encoding")
+			if err != nil {
+				return usageError(err)
+			}
+
+			keyData, err := os.ReadFile(keyFile)
+			if err != nil {
+				return usageError(errors.Wrap(err, "failed to read key file"))
+			}
+
+			privateKey, err := ocmf.ParsePrivateKey(keyData)
+			if err != nil {
+				return usageError(err)
+			}
+
+			payload, err := readPayload(cmd, payloadFile)
+			if err != nil {
+				return usageError(err)
+			}
+
+			builder, err := ocmf.NewBuilderE(privateKey,
+				ocmf.WithSignatureAlgorithm(ocmf.SignatureAlgorithm(algorithm)),
+				ocmf.WithSignatureEncoding(ocmf.SignatureEncoding(encoding)),
+			)
+			if err != nil {
+				return usageError(err)
+			}
+
+			message, err := builder.WithPayload(*payload).Build()
+			if err != nil {
+				// Show which fields are wrong
+				for _, fieldError := range ocmf.FieldErrors(err) {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  %s: %s\n", fieldError.Field, fieldError.Message)
+				}
+
+				return &exitError{code: exitFailure, err: err}
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), message.String())
+			return nil
+		},
+	}
+
+	cmd.Flags().String("key-file", "", "private key of the meter (PEM or DER)")
+	cmd.Flags().StringP("payload", "p", "-", "file containing the payload section as JSON or YAML, - for stdin")
+	cmd.Flags().StringP("algorithm", "a", string(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256), "signature algorithm")
+	cmd.Flags().StringP("encoding", "e", string(ocmf.SignatureEncodingHex), "signature encoding (hex or base64)")
+	_ = cmd.MarkFlagRequired("key-file")
+	return cmd
+}
+
+func readPayload(cmd *cobra.Command, file string) (*ocmf.PayloadSection, error) {
+	var data []byte
+	var err error
+
+	if file == "-" {
+		data, err = io.ReadAll(io.LimitReader(cmd.InOrStdin(), maxMessageSize))
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read payload")
+	}
+
+	return decodePayload(data)
+}
+
+// decodePayload decodes a JSON or YAML payload. YAML is converted to JSON first,
+// so the OCMF field names from the json tags apply to both formats.
+func decodePayload(data []byte) (*ocmf.PayloadSection, error) {
+	payload := ocmf.PayloadSection{}
+	if json.Valid(data) {
+		err := json.Unmarshal(data, &payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode JSON payload")
+		}
+
+		return &payload, nil
+	}
+
+	var document any
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode YAML payload")
+	}
+
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert YAML payload")
+	}
+
+	err = json.Unmarshal(converted, &payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode YAML payload")
+	}
+
+	return &payload, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+const yamlPayload = `
+PG: T1
+MS: exampleSerial123
+IS: true
+IT: RFID_PLAIN
+ID: 1F2D3A4F5506C7
+RD:
+  - TM: "2018-07-24T13:22:04,000+0200 S"
+    TX: B
+    RV: 2935.6
+    RI: "1-b:1.8.0"
+    RU: kWh
+    ST: G
+`
+
+type signTestSuite struct {
+	suite.Suite
+	keyPrefix string
+}
+
+func (s *signTestSuite) SetupTest() {
+	s.keyPrefix = filepath.Join(s.T().TempDir(), "meter")
+	code, _, _ := execute("", "keygen", "--out", s.keyPrefix)
+	s.Require().Equal(0, code)
+}
+
+func (s *signTestSuite) TestSign_yaml() {
+	code, stdout, stderr := execute(yamlPayload, "sign", "--key-file", s.keyPrefix+".key", "--encoding", "base64")
+	s.Require().Equal(0, code, stderr)
+
+	message, err := ocmf.ParseMessage(strings.TrimSpace(stdout))
+	s.Require().NoError(err)
+	s.Equal("exampleSerial123", message.Payload.MeterSerial)
+	s.Equal(2935.6, message.Payload.Readings[0].ReadingValue)
+	s.Equal(ocmf.SignatureEncodingBase64, message.Signature.Encoding)
+
+	code, stdout, _ = execute("", "verify", "--key-file", s.keyPrefix+".pub.hex", strings.TrimSpace(stdout))
+	s.Equal(0, code)
+	s.Equal("arg:1: OK\n", stdout)
+}
+
+func (s *signTestSuite) TestSign_json() {
+	payload := `{"PG":"T2","MS":"exampleSerial123","IT":"RFID_NONE","RD":[{"TM":"2018-07-24T13:22:04,000+0200 S","RV":1,"RU":"kWh","ST":"G"}]}`
+
+	code, stdout, stderr := execute(payload, "sign", "--key-file", s.keyPrefix+".key", "--payload", "-")
+	s.Require().Equal(0, code, stderr)
+	s.True(strings.HasPrefix(stdout, `OCMF|{"FV":"0.4","PG":"T2"`))
+}
+
+func (s *signTestSuite) TestSign_invalid() {
+	code, _, stderr := execute(`{"PG":"T1"}`, "sign", "--key-file", s.keyPrefix+".key")
+	s.Equal(exitFailure, code)
+	s.Contains(stderr, "  MS: MS is required")
+
+	code, _, stderr = execute(yamlPayload, "sign", "--key-file", s.keyPrefix+".key",
+		"--algorithm", string(ocmf.SignatureAlgorithmECDSAsecp384r1SHA256))
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "key does not match the signature algorithm")
+
+	code, _, stderr = execute("PG: [", "sign", "--key-file", s.keyPrefix+".key")
+	s.Equal(exitUsage, code)
+	s.Contains(stderr, "failed to decode YAML payload")
+
+	code, _, _ = execute(yamlPayload, "sign", "--key-file", s.keyPrefix+".pub")
+	s.Equal(exitUsage, code)
+
+	code, _, _ = execute(yamlPayload, "sign")
+	s.Equal(exitUsage, code)
+}
+
+func TestSign(t *testing.T) {
+	suite.Run(t, new(signTestSuite))
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/ChargePi/ocmf-go/simulator"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newSimulateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate a meter and print the signed messages of its transactions",
+		Long: `Simulate a meter and print the signed messages of its transactions, one per line.
+
+Power profiles:
+  constant:<watts>
+  ramp:<from watts>:<to watts>:<duration>
+  taper:<watts>:<start>:<half life>
+
+Faults are injected into a message by its number, e.g. T@3 (timeout), D@3 (disconnected),
+M@3 (manipulated) or reset@3 (clock reset).
+
+Without a key file, a new key is generated and its public key is printed to stderr.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := simulatorConfig(cmd)
+			if err != nil {
+				return usageError(err)
+			}
+
+			sessions, err := cmd.Flags().GetInt("sessions")
+			if err != nil {
+				return usageError(err)
+			}
+
+			intermediates, err := cmd.Flags().GetInt("intermediate")
+			if err != nil {
+				return usageError(err)
+			}
+
+			idle, err := cmd.Flags().GetDuration("idle")
+			if err != nil {
+				return usageError(err)
+			}
+
+			meter, err := simulator.NewMeter(*config)
+			if err != nil {
+				return usageError(err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			emit := func(message ocmf.Message) error {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), message.String())
+				return err
+			}
+
+			for i := 0; i < sessions; i++ {
+				if i > 0 {
+					if err := meter.Idle(idle); err != nil {
+						return &exitError{code: exitFailure, err: err}
+					}
+				}
+
+				if err := meter.Run(ctx, intermediates, emit); err != nil {
+					return &exitError{code: exitFailure, err: err}
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("serial", "SIM-0001", "meter serial")
+	cmd.Flags().String("key-file", "", "private key of the meter")
+	cmd.Flags().Int("sessions", 1, "number of transactions")
+	cmd.Flags().Int("intermediate", 4, "number of intermediate messages per transaction")
+	cmd.Flags().Duration("interval", time.Minute, "meter time between messages")
+	cmd.Flags().Duration("idle", 15*time.Minute, "meter time between transactions")
+	cmd.Flags().Duration("pace", 0, "real time between messages")
+	cmd.Flags().String("profile", "constant:11000", "power profile")
+	cmd.Flags().Float64("register", 1000, "initial register value in Wh")
+	cmd.Flags().String("start", "", "initial meter time in RFC 3339 format (default now)")
+	cmd.Flags().StringArray("fault", nil, "fault to inject, e.g. T@3 (repeatable)")
+	cmd.Flags().String("identification-type", string(ocmf.RfidNone), "identification type (IT)")
+	cmd.Flags().String("identification-data", "", "identification data (ID)")
+	return cmd
+}
+
+func simulatorConfig(cmd *cobra.Command) (*simulator.Config, error) {
+	flags := cmd.Flags()
+
+	serial, err := flags.GetString("serial")
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile, err := flags.GetString("key-file")
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := flags.GetDuration("interval")
+	if err != nil {
+		return nil, err
+	}
+
+	pace, err := flags.GetDuration("pace")
+	if err != nil {
+		return nil, err
+	}
+
+	profileDescription, err := flags.GetString("profile")
+	if err != nil {
+		return nil, err
+	}
+
+	register, err := flags.GetFloat64("register")
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := flags.GetString("start")
+	if err != nil {
+		return nil, err
+	}
+
+	faultDescriptions, err := flags.GetStringArray("fault")
+	if err != nil {
+		return nil, err
+	}
+
+	identificationType, err := flags.GetString("identification-type")
+	if err != nil {
+		return nil, err
+	}
+
+	identificationData, err := flags.GetString("identification-data")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := simulator.ParseProfile(profileDescription)
+	if err != nil {
+		return nil, err
+	}
+
+	faults := []simulator.Fault{}
+	for _, description := range faultDescriptions {
+		fault, err := simulator.ParseFault(description)
+		if err != nil {
+			return nil, err
+		}
+		faults = append(faults, fault)
+	}
+
+	startTime := time.Now()
+	if start != "" {
+		startTime, err = time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid start time")
+		}
+	}
+
+	privateKey, err := simulatorKey(cmd, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &simulator.Config{
+		MeterSerial:        serial,
+		MeterVendor:        "ocmf-go",
+		MeterModel:         "simulator",
+		PrivateKey:         privateKey,
+		IdentificationType: identificationType,
+		IdentificationData: identificationData,
+		Register:           register,
+		Profile:            profile,
+		StartTime:          startTime,
+		Interval:           interval,
+		Pace:               pace,
+		Faults:             faults,
+	}, nil
+}
+
+func simulatorKey(cmd *cobra.Command, keyFile string) (*ecdsa.PrivateKey, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read key file")
+		}
+
+		return ocmf.ParsePrivateKey(data)
+	}
+
+	privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := ocmf.MarshalPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.PrintErrf("Public key (hex): %s\n", hex.EncodeToString(der))
+	return privateKey, nil
+}
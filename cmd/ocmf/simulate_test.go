@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type simulateTestSuite struct {
+	suite.Suite
+}
+
+func (s *simulateTestSuite) TestSimulate() {
+	code, stdout, stderr := execute("", "simulate",
+		"--sessions", "2",
+		"--intermediate", "1",
+		"--start", "2024-05-01T10:00:00+02:00",
+		"--fault", "T@2",
+	)
+	s.Equal(0, code)
+	s.Contains(stderr, "Public key (hex): 3059")
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	s.Require().Len(lines, 6)
+
+	transactions := []string{}
+	for i, line := range lines {
+		message, err := ocmf.ParseMessage(line)
+		s.Require().NoError(err)
+		s.NoError(message.Validate())
+		s.Equal("SIM-0001", message.Payload.MeterSerial)
+		s.Equal(fmt.Sprintf("T%d", i+1), message.Payload.Pagination)
+
+		transactions = append(transactions, message.Payload.Readings[0].Transaction)
+		if i == 1 {
+			s.Equal(string(ocmf.MeterTimeout), message.Payload.Readings[0].Status)
+		}
+	}
+	s.Equal([]string{"B", "C", "E", "B", "C", "E"}, transactions)
+
+	// The output can be verified with the printed key
+	key := strings.TrimSpace(strings.TrimPrefix(stderr, "Public key (hex): "))
+	code, _, _ = execute(stdout, "verify", "--key", key)
+	s.Equal(0, code)
+}
+
+func (s *simulateTestSuite) TestSimulateInvalidFlags() {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "Profile", args: []string{"--profile", "random"}, expected: "invalid power profile"},
+		{name: "Fault", args: []string{"--fault", "G@1"}, expected: "invalid fault"},
+		{name: "Start", args: []string{"--start", "yesterday"}, expected: "invalid start time"},
+		{name: "Key file", args: []string{"--key-file", "missing.key"}, expected: "failed to read key file"},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			code, _, stderr := execute("", append([]string{"simulate"}, tt.args...)...)
+			s.Equal(exitUsage, code)
+			s.Contains(stderr, tt.expected)
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	suite.Run(t, new(simulateTestSuite))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [message...]",
+		Short: "Validate messages and print field level diagnostics",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs, err := readInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			results := []result{}
+			for _, in := range inputs {
+				results = append(results, validate(in))
+			}
+
+			return printResults(cmd, results)
+		},
+	}
+
+	addInputFlags(cmd)
+	addOutputFlags(cmd)
+	return cmd
+}
+
+func validate(in input) result {
+	result := result{Source: in.source}
+
+	message, err := ocmf.ParseMessage(in.data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.MeterSerial = message.Payload.MeterSerial
+	result.Pagination = message.Payload.Pagination
+
+	diagnostics := append(ocmf.FieldErrors(message.Payload.Validate()), ocmf.FieldErrors(message.Signature.WithDefaults().Validate())...)
+	if len(diagnostics) > 0 {
+		result.Error = "validation failed"
+		result.Diagnostics = diagnostics
+		return result
+	}
+
+	result.Valid = true
+	return result
+}
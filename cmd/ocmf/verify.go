@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"os"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [message...]",
+		Short: "Verify the signature of messages against a public key",
+		Long: `Verify the signature of messages against a public key.
+
+The key can be PEM, DER or an uncompressed curve point, either raw, hex or base64 encoded.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			publicKey, err := readPublicKey(cmd)
+			if err != nil {
+				return usageError(err)
+			}
+
+			skipValidation, err := cmd.Flags().GetBool("skip-validation")
+			if err != nil {
+				return usageError(err)
+			}
+
+			inputs, err := readInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			results := []result{}
+			for _, in := range inputs {
+				results = append(results, verify(in, publicKey, !skipValidation))
+			}
+
+			return printResults(cmd, results)
+		},
+	}
+
+	cmd.Flags().StringP("key", "k", "", "public key of the meter")
+	cmd.Flags().String("key-file", "", "file containing the public key of the meter")
+	cmd.Flags().Bool("skip-validation", false, "only verify the signature, without validating the message")
+	cmd.MarkFlagsOneRequired("key", "key-file")
+	cmd.MarkFlagsMutuallyExclusive("key", "key-file")
+
+	addInputFlags(cmd)
+	addOutputFlags(cmd)
+	return cmd
+}
+
+func readPublicKey(cmd *cobra.Command) (*ecdsa.PublicKey, error) {
+	key, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile, err := cmd.Flags().GetString("key-file")
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte(key)
+	if keyFile != "" {
+		data, err = os.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read key file")
+		}
+	}
+
+	return ocmf.ParsePublicKey(data)
+}
+
+func verify(in input, publicKey *ecdsa.PublicKey, withValidation bool) result {
+	result := result{Source: in.source}
+
+	opts := []ocmf.Opt{ocmf.WithAutomaticSignatureVerification(publicKey)}
+	if withValidation {
+		opts = append(opts, ocmf.WithAutomaticValidation())
+	}
+
+	parser := ocmf.NewParser(opts...).ParseOcmfMessageFromString(in.data)
+	message, err := parser.GetMessage()
+	if err != nil {
+		result.Error = err.Error()
+		result.Diagnostics = ocmf.FieldErrors(err)
+
+		// Identify the message even if it failed
+		if parsed, parseErr := ocmf.ParseMessage(in.data); parseErr == nil {
+			result.MeterSerial = parsed.Payload.MeterSerial
+			result.Pagination = parsed.Payload.Pagination
+		}
+
+		return result
+	}
+
+	result.Valid = true
+	result.MeterSerial = message.Payload.MeterSerial
+	result.Pagination = message.Payload.Pagination
+	return result
+}
@@ -0,0 +1,190 @@
+// Package conformance runs OCMF messages against their expected parse, validation and verification results.
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+//go:embed corpus
+var corpus embed.FS
+
+// Corpus returns the built-in corpus of vectors.
+func Corpus() fs.FS {
+	sub, _ := fs.Sub(corpus, "corpus")
+	return sub
+}
+
+// Vector is a single message with the expected results.
+type Vector struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Source is the publication a message was copied from verbatim, e.g. the OCMF specification or a vendor manual.
+	// It is empty for messages signed with the test keys of the corpus.
+	Source  string `json:"source,omitempty"`
+	Message string `json:"message"`
+	// PublicKey in any encoding supported by ocmf.ParsePublicKey. Required if the verification result is expected.
+	PublicKey string      `json:"publicKey,omitempty"`
+	Expect    Expectation `json:"expect"`
+	// File is the path of the vector in the corpus.
+	File string `json:"-"`
+}
+
+// Expectation holds the expected results. Empty fields are not checked.
+type Expectation struct {
+	Parse bool  `json:"parse"`
+	Valid *bool `json:"valid,omitempty"`
+	// InvalidFields are the fields reported by the validation, e.g. "RD[0].TM".
+	InvalidFields []string                `json:"invalidFields,omitempty"`
+	Verified      *bool                   `json:"verified,omitempty"`
+	MeterSerial   string                  `json:"meterSerial,omitempty"`
+	Pagination    string                  `json:"pagination,omitempty"`
+	Readings      int                     `json:"readings,omitempty"`
+	Algorithm     ocmf.SignatureAlgorithm `json:"algorithm,omitempty"`
+}
+
+type Result struct {
+	Vector   Vector   `json:"-"`
+	Name     string   `json:"name"`
+	File     string   `json:"file,omitempty"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Load reads all vectors from the .json files of the file system. Each file holds a single vector.
+func Load(fsys fs.FS) ([]Vector, error) {
+	vectors := []Vector{}
+
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || path.Ext(filePath) != ".json" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", filePath)
+		}
+
+		vector := Vector{}
+		if err := json.Unmarshal(data, &vector); err != nil {
+			return errors.Wrapf(err, "failed to decode %s", filePath)
+		}
+
+		vector.File = filePath
+		if vector.Name == "" {
+			vector.Name = filePath
+		}
+
+		vectors = append(vectors, vector)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vectors, nil
+}
+
+// RunCorpus loads and runs all vectors of the file system.
+func RunCorpus(fsys fs.FS) ([]Result, error) {
+	vectors, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(vectors))
+	for _, vector := range vectors {
+		results = append(results, Run(vector))
+	}
+
+	return results, nil
+}
+
+// Run parses, validates and verifies the message of the vector and compares the outcome with the expectation.
+func Run(vector Vector) Result {
+	result := Result{Vector: vector, Name: vector.Name, File: vector.File}
+	fail := func(format string, args ...any) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+	expect := vector.Expect
+
+	message, err := ocmf.ParseMessage(vector.Message)
+	if (err == nil) != expect.Parse {
+		fail("parse: expected %t, got error %v", expect.Parse, err)
+	}
+	if err != nil {
+		return result
+	}
+
+	if expect.MeterSerial != "" && message.Payload.MeterSerial != expect.MeterSerial {
+		fail("meter serial: expected %q, got %q", expect.MeterSerial, message.Payload.MeterSerial)
+	}
+
+	if expect.Pagination != "" && message.Payload.Pagination != expect.Pagination {
+		fail("pagination: expected %q, got %q", expect.Pagination, message.Payload.Pagination)
+	}
+
+	if expect.Readings != 0 && len(message.Payload.Readings) != expect.Readings {
+		fail("readings: expected %d, got %d", expect.Readings, len(message.Payload.Readings))
+	}
+
+	if algorithm := message.Signature.WithDefaults().Algorithm; expect.Algorithm != "" && algorithm != expect.Algorithm {
+		fail("algorithm: expected %s, got %s", expect.Algorithm, algorithm)
+	}
+
+	fieldErrors := append(ocmf.FieldErrors(message.Payload.Validate()), ocmf.FieldErrors(message.Signature.WithDefaults().Validate())...)
+	if expect.Valid != nil && (len(fieldErrors) == 0) != *expect.Valid {
+		fail("valid: expected %t, got %v", *expect.Valid, fieldErrors)
+	}
+
+	if expect.InvalidFields != nil {
+		fields := []string{}
+		for _, fieldError := range fieldErrors {
+			fields = append(fields, fieldError.Field)
+		}
+
+		expected := slices.Clone(expect.InvalidFields)
+		sort.Strings(fields)
+		sort.Strings(expected)
+		if !slices.Equal(fields, expected) {
+			fail("invalid fields: expected %v, got %v", expected, fields)
+		}
+	}
+
+	if expect.Verified != nil {
+		verified, err := verify(message, vector.PublicKey)
+		if verified != *expect.Verified {
+			fail("verified: expected %t, got %t (%v)", *expect.Verified, verified, err)
+		}
+	}
+
+	return result
+}
+
+func verify(message *ocmf.Message, publicKey string) (bool, error) {
+	if publicKey == "" {
+		return false, ocmf.ErrPublicKeyMissing
+	}
+
+	key, err := ocmf.ParsePublicKey([]byte(publicKey))
+	if err != nil {
+		return false, err
+	}
+
+	return message.Verify(key)
+}
@@ -0,0 +1,110 @@
+package conformance
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type conformanceTestSuite struct {
+	suite.Suite
+}
+
+func (s *conformanceTestSuite) TestLoad() {
+	vectors, err := Load(Corpus())
+	s.Require().NoError(err)
+	s.Len(vectors, 19)
+	s.Equal("vectors/01-full-payload-hex.json", vectors[0].File)
+
+	_, err = Load(fstest.MapFS{"broken.json": {Data: []byte("{")}})
+	s.ErrorContains(err, "broken.json")
+
+	vectors, err = Load(fstest.MapFS{"unnamed.json": {Data: []byte(`{"message":"OCMF","source":"OCMF 1.0"}`)}, "notes.txt": {}})
+	s.Require().NoError(err)
+	s.Require().Len(vectors, 1)
+	s.Equal("unnamed.json", vectors[0].Name)
+	s.Equal("OCMF 1.0", vectors[0].Source)
+}
+
+func (s *conformanceTestSuite) TestRun() {
+	valid, verified := true, true
+
+	vectors, err := Load(Corpus())
+	s.Require().NoError(err)
+	vector := vectors[0]
+
+	s.True(Run(vector).Passed())
+
+	// Wrong expectations are reported
+	vector.Expect.Pagination = "T1"
+	vector.Expect.Readings = 2
+	vector.Expect.InvalidFields = []string{"MS"}
+	result := Run(vector)
+	s.False(result.Passed())
+	s.Len(result.Failures, 3)
+
+	// Verification requires a key
+	vector = vectors[0]
+	vector.PublicKey = ""
+	s.Equal([]string{"verified: expected true, got false (public key is missing)"}, Run(vector).Failures)
+
+	result = Run(Vector{Message: "OCMF|{}", Expect: Expectation{Parse: true, Valid: &valid, Verified: &verified}})
+	s.Require().Len(result.Failures, 1)
+	s.Contains(result.Failures[0], "parse: expected true")
+}
+
+func (s *conformanceTestSuite) TestRunCorpus() {
+	results, err := RunCorpus(Corpus())
+	s.Require().NoError(err)
+
+	for _, result := range results {
+		s.True(result.Passed(), "%s: %v", result.File, result.Failures)
+	}
+}
+
+// TestSources checks that only vectors copied verbatim from a publication name a source, and that these are not
+// signed with the test keys of the corpus.
+func (s *conformanceTestSuite) TestSources() {
+	testKeys, err := fs.Glob(Corpus(), "keys/*.pub.hex")
+	s.Require().NoError(err)
+	s.Require().NotEmpty(testKeys)
+
+	vectors, err := Load(Corpus())
+	s.Require().NoError(err)
+
+	for _, vector := range vectors {
+		if vector.PublicKey == "" {
+			continue
+		}
+
+		publicKey, err := ocmf.ParsePublicKey([]byte(vector.PublicKey))
+		s.Require().NoError(err, vector.File)
+
+		signedWithTestKey := false
+		for _, file := range testKeys {
+			data, err := fs.ReadFile(Corpus(), file)
+			s.Require().NoError(err)
+
+			testKey, err := ocmf.ParsePublicKey(data)
+			s.Require().NoError(err, file)
+			signedWithTestKey = signedWithTestKey || testKey.Equal(publicKey)
+		}
+
+		if signedWithTestKey {
+			s.Empty(vector.Source, "%s is signed with a test key and cannot be copied from a publication", vector.File)
+		} else {
+			s.NotEmpty(vector.Source, "%s is not signed with a test key and must name its source", vector.File)
+		}
+	}
+}
+
+func TestConformance(t *testing.T) {
+	suite.Run(t, new(conformanceTestSuite))
+}
+
+func TestCorpus(t *testing.T) {
+	RunTests(t, Corpus())
+}
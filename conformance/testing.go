@@ -0,0 +1,32 @@
+package conformance
+
+import (
+	"io/fs"
+	"testing"
+)
+
+// RunTests runs every vector of the file system as a subtest, so other projects can check their corpus with go test:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.RunTests(t, os.DirFS("testdata/ocmf"))
+//	}
+func RunTests(t *testing.T, fsys fs.FS) {
+	t.Helper()
+
+	vectors, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(vectors) == 0 {
+		t.Fatal("no vectors found")
+	}
+
+	for _, vector := range vectors {
+		t.Run(vector.Name, func(t *testing.T) {
+			for _, failure := range Run(vector).Failures {
+				t.Errorf("%s: %s", vector.File, failure)
+			}
+		})
+	}
+}
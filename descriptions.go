@@ -0,0 +1,223 @@
+package ocmf_go
+
+import "strings"
+
+type Language string
+
+const (
+	LanguageEnglish = Language("en")
+	LanguageGerman  = Language("de")
+)
+
+type description[T ~string] struct {
+	value   T
+	english string
+	german  string
+}
+
+type descriptions[T ~string] []description[T]
+
+// describe returns the description of the value in the language, falling back to English
+// for unknown languages and to the value itself for unknown values.
+func (d descriptions[T]) describe(value T, language Language) string {
+	for _, entry := range d {
+		if entry.value != value {
+			continue
+		}
+
+		if language == LanguageGerman {
+			return entry.german
+		}
+
+		return entry.english
+	}
+
+	return string(value)
+}
+
+// list returns all values with their descriptions, e.g. "G (OK), T (Timeout)".
+func (d descriptions[T]) list(language Language) string {
+	values := make([]string, 0, len(d))
+	for _, entry := range d {
+		values = append(values, string(entry.value)+" ("+d.describe(entry.value, language)+")")
+	}
+
+	return strings.Join(values, ", ")
+}
+
+var meterErrorDescriptions = descriptions[MeterError]{
+	{MeterNotPresent, "Not present", "Nicht vorhanden"},
+	{MeterOk, "OK", "In Ordnung"},
+	{MeterTimeout, "Timeout", "Zeitüberschreitung"},
+	{MeterDisconnected, "Disconnected", "Verbindung getrennt"},
+	{MeterRemoved, "Removed", "Entfernt"},
+	{MeterManipulated, "Manipulated", "Manipuliert"},
+	{MeterExchanged, "Exchanged", "Ausgetauscht"},
+	{MeterIncompatible, "Incompatible", "Inkompatibel"},
+	{MeterOutOfRange, "Out of range", "Außerhalb des Messbereichs"},
+	{MeterSubstitute, "Substitute value", "Ersatzwert"},
+	{MeterOtherError, "Other error", "Sonstiger Fehler"},
+	{MeterReadError, "Read error", "Lesefehler"},
+}
+
+func (m MeterError) Describe(language Language) string {
+	return meterErrorDescriptions.describe(m, language)
+}
+
+func (m MeterError) String() string {
+	return string(m)
+}
+
+var userAssignmentStateDescriptions = descriptions[UserAssignmentState]{
+	{UserAssignmentStateNONE, "No user assignment", "Keine Benutzerzuordnung"},
+	{UserAssignmentStateHearsay, "Assigned without proof", "Zuordnung ohne Nachweis"},
+	{UserAssignmentStateTrusted, "Assigned by a trusted source", "Zuordnung durch vertrauenswürdige Quelle"},
+	{UserAssignmentStateVerified, "Assignment verified by the charge point", "Zuordnung durch den Ladepunkt geprüft"},
+	{UserAssignmentStateCertified, "Assignment certified", "Zuordnung zertifiziert"},
+	{UserAssignmentStateSecure, "Assignment secured by a cryptographic method", "Zuordnung kryptographisch gesichert"},
+	{UserAssignmentStateMismatch, "Identification data mismatch", "Identifikationsdaten stimmen nicht überein"},
+	{UserAssignmentStateInvalid, "Certificate invalid", "Zertifikat ungültig"},
+	{UserAssignmentStateOutdated, "Certificate expired", "Zertifikat abgelaufen"},
+	{UserAssignmentStateUnknown, "Certificate could not be checked", "Zertifikat konnte nicht geprüft werden"},
+}
+
+func (s UserAssignmentState) Describe(language Language) string {
+	return userAssignmentStateDescriptions.describe(s, language)
+}
+
+func (s UserAssignmentState) String() string {
+	return string(s)
+}
+
+var ocppStateDescriptions = descriptions[OcppState]{
+	{OcppNone, "No OCPP identification", "Keine OCPP-Identifikation"},
+	{OcppRemoteStart, "Remote start", "Fernstart"},
+	{OcppAuthorizeMethod, "Authorized by the backend", "Autorisierung durch das Backend"},
+	{OcppRemoteStartTLS, "Remote start over TLS", "Fernstart über TLS"},
+	{OcppAuthorizeMethodTLS, "Authorized by the backend over TLS", "Autorisierung durch das Backend über TLS"},
+	{OcppCache, "Authorized from the local cache", "Autorisierung aus dem lokalen Cache"},
+	{OcppWhiteList, "Authorized by the local whitelist", "Autorisierung durch die lokale Whitelist"},
+	{OcppCertified, "Authorized by a certified backend", "Autorisierung durch ein zertifiziertes Backend"},
+}
+
+func (s OcppState) Describe(language Language) string {
+	return ocppStateDescriptions.describe(s, language)
+}
+
+func (s OcppState) String() string {
+	return string(s)
+}
+
+var rfidStateDescriptions = descriptions[RfidState]{
+	{RfidNone, "No identification", "Keine Identifikation"},
+	{RfidPlain, "RFID card", "RFID-Karte"},
+	{RfidRelated, "RFID card with related data", "RFID-Karte mit Bezugsdaten"},
+	{RfidPreSharedKey, "RFID card with pre-shared key", "RFID-Karte mit Pre-Shared Key"},
+}
+
+func (s RfidState) Describe(language Language) string {
+	return rfidStateDescriptions.describe(s, language)
+}
+
+func (s RfidState) String() string {
+	return string(s)
+}
+
+var timeStatusDescriptions = descriptions[TimeStatus]{
+	{TimeStatusUnknown, "Unknown", "Unbekannt"},
+	{TimeStatusInformative, "Informative", "Informativ"},
+	{TimeStatusSynchronized, "Synchronized", "Synchronisiert"},
+	{TimeStatusRelative, "Relative", "Relativ"},
+}
+
+func (ts TimeStatus) Describe(language Language) string {
+	return timeStatusDescriptions.describe(ts, language)
+}
+
+func (ts TimeStatus) String() string {
+	return string(ts)
+}
+
+var transactionTypeDescriptions = descriptions[TransactionType]{
+	{TransactionBegin, "Transaction begin", "Transaktionsbeginn"},
+	{TransactionCharging, "Charging", "Ladevorgang"},
+	{TransactionException, "Exception", "Ausnahme"},
+	{TransactionEnd, "Transaction end", "Transaktionsende"},
+	{TransactionTerminatedLocal, "Terminated locally", "Lokal beendet"},
+	{TransactionTerminatedRemote, "Terminated remotely", "Fernbeendet"},
+	{TransactionTerminatedAbort, "Aborted", "Abgebrochen"},
+	{TransactionTerminatedPowerFailure, "Terminated by power failure", "Durch Stromausfall beendet"},
+	{TransactionSuspended, "Suspended", "Unterbrochen"},
+	{TransactionTariffChange, "Tariff change", "Tarifwechsel"},
+}
+
+func (t TransactionType) Describe(language Language) string {
+	return transactionTypeDescriptions.describe(t, language)
+}
+
+func (t TransactionType) String() string {
+	return string(t)
+}
+
+var iso15118StateDescriptions = descriptions[ISO15118State]{
+	{ISO15118None, "No ISO 15118 identification", "Keine ISO-15118-Identifikation"},
+	{ISO15118PlugAndCharge, "Plug & Charge", "Plug & Charge"},
+}
+
+func (s ISO15118State) Describe(language Language) string {
+	return iso15118StateDescriptions.describe(s, language)
+}
+
+func (s ISO15118State) String() string {
+	return string(s)
+}
+
+var chargePointAssignmentTypeDescriptions = descriptions[ChargePointAssignmentType]{
+	{ChargePointAssignmentTypeEVSEID, "EVSE ID", "EVSE-ID"},
+	{ChargePointAssignmentTypeCBIDC, "Calibration law ID of the charge point", "Eichrechtliche Kennung des Ladepunkts"},
+}
+
+func (t ChargePointAssignmentType) Describe(language Language) string {
+	return chargePointAssignmentTypeDescriptions.describe(t, language)
+}
+
+func (t ChargePointAssignmentType) String() string {
+	return string(t)
+}
+
+var unitsDescriptions = descriptions[Units]{
+	{UnitsWh, "Watt hours", "Wattstunden"},
+	{UnitskWh, "Kilowatt hours", "Kilowattstunden"},
+	{UnitsMilliOhm, "Milliohm", "Milliohm"},
+	{UnitsMicroOhm, "Microohm", "Mikroohm"},
+}
+
+func (u Units) Describe(language Language) string {
+	return unitsDescriptions.describe(u, language)
+}
+
+func (u Units) String() string {
+	return string(u)
+}
+
+var currentTypeDescriptions = descriptions[CurrentType]{
+	{CurrentTypeAC, "Alternating current", "Wechselstrom"},
+	{CurrentTypeDC, "Direct current", "Gleichstrom"},
+}
+
+func (ct CurrentType) Describe(language Language) string {
+	return currentTypeDescriptions.describe(ct, language)
+}
+
+func (ct CurrentType) String() string {
+	return string(ct)
+}
+
+func (d descriptions[T]) values() []string {
+	values := make([]string, 0, len(d))
+	for _, entry := range d {
+		values = append(values, string(entry.value))
+	}
+
+	return values
+}
@@ -0,0 +1,140 @@
+package ocmf_go
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type descriptionsTestSuite struct {
+	suite.Suite
+}
+
+func (s *descriptionsTestSuite) TestDescribe() {
+	tests := []struct {
+		name     string
+		value    interface{ Describe(Language) string }
+		english  string
+		german   string
+		fallback string
+	}{
+		{name: "Meter error", value: MeterManipulated, english: "Manipulated", german: "Manipuliert"},
+		{name: "User assignment state", value: UserAssignmentStateOutdated, english: "Certificate expired", german: "Zertifikat abgelaufen"},
+		{name: "OCPP state", value: OcppCache, english: "Authorized from the local cache", german: "Autorisierung aus dem lokalen Cache"},
+		{name: "RFID state", value: RfidPlain, english: "RFID card", german: "RFID-Karte"},
+		{name: "Time status", value: TimeStatusSynchronized, english: "Synchronized", german: "Synchronisiert"},
+		{name: "Transaction type", value: TransactionTerminatedPowerFailure, english: "Terminated by power failure", german: "Durch Stromausfall beendet"},
+		{name: "ISO 15118 state", value: ISO15118PlugAndCharge, english: "Plug & Charge", german: "Plug & Charge"},
+		{name: "Charge point assignment", value: ChargePointAssignmentTypeEVSEID, english: "EVSE ID", german: "EVSE-ID"},
+		{name: "Unit", value: UnitskWh, english: "Kilowatt hours", german: "Kilowattstunden"},
+		{name: "Current type", value: CurrentTypeDC, english: "Direct current", german: "Gleichstrom"},
+		{name: "Unknown value", value: MeterError("Z"), english: "Z", german: "Z"},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.Equal(tt.english, tt.value.Describe(LanguageEnglish))
+			s.Equal(tt.german, tt.value.Describe(LanguageGerman))
+			// Unknown languages fall back to English
+			s.Equal(tt.english, tt.value.Describe("fr"))
+		})
+	}
+}
+
+func (s *descriptionsTestSuite) TestFormat() {
+	// Formatting a value yields its OCMF code, not the description
+	s.Equal("M", fmt.Sprint(MeterManipulated))
+	s.Equal("UNKNOWN", fmt.Sprintf("%s", UserAssignmentStateUnknown))
+	s.Equal("OCPP_CACHE", fmt.Sprintf("%v", OcppCache))
+	s.Equal("RFID_PLAIN", fmt.Sprint(RfidPlain))
+	s.Equal("S", fmt.Sprint(TimeStatusSynchronized))
+	s.Equal("B", fmt.Sprint(TransactionBegin))
+
+	tests := []struct {
+		value    fmt.Stringer
+		expected string
+	}{
+		{MeterManipulated, "M"},
+		{UserAssignmentStateUnknown, "UNKNOWN"},
+		{OcppCache, "OCPP_CACHE"},
+		{RfidPlain, "RFID_PLAIN"},
+		{TimeStatusSynchronized, "S"},
+		{TransactionTerminatedPowerFailure, "P"},
+		{ISO15118PlugAndCharge, "ISO15118_PNC"},
+		{ChargePointAssignmentTypeEVSEID, "EVSE_ID"},
+		{UnitskWh, "kWh"},
+		{CurrentTypeDC, "DC"},
+	}
+
+	for _, tt := range tests {
+		s.Equal(tt.expected, tt.value.String())
+	}
+}
+
+func (s *descriptionsTestSuite) TestAllValuesDescribed() {
+	all := map[string][]descriptionEntry{
+		"meterError":            entries(meterErrorDescriptions, isValidMeterError),
+		"userAssignmentState":   entries(userAssignmentStateDescriptions, isValidUserAssignmentState),
+		"ocppState":             entries(ocppStateDescriptions, isValidOcppState),
+		"rfidState":             entries(rfidStateDescriptions, isValidRfidState),
+		"timeStatus":            entries(timeStatusDescriptions, isValidTimeStatus),
+		"transactionType":       entries(transactionTypeDescriptions, isValidTransactionType),
+		"iso5118State":          entries(iso15118StateDescriptions, isValidISO15118State),
+		"chargePointAssignment": entries(chargePointAssignmentTypeDescriptions, isValidChargePointAssignmentType),
+		"unit":                  entries(unitsDescriptions, isValidUnit),
+		"currentType":           entries(currentTypeDescriptions, isValidCurrentType),
+	}
+
+	for rule, descriptions := range all {
+		s.T().Run(rule, func(t *testing.T) {
+			s.Contains(enumRules, rule)
+			for _, entry := range descriptions {
+				s.True(entry.valid, "%s is not a valid value", entry.value)
+				s.NotEmpty(entry.english, entry.value)
+				s.NotEmpty(entry.german, entry.value)
+			}
+		})
+	}
+
+	// Every valid value must have a description
+	for _, value := range []MeterError{MeterNotPresent, MeterOk, MeterTimeout, MeterDisconnected, MeterRemoved, MeterManipulated,
+		MeterExchanged, MeterIncompatible, MeterOutOfRange, MeterSubstitute, MeterOtherError, MeterReadError} {
+		s.NotEqual(string(value), value.Describe(LanguageEnglish))
+	}
+	s.Len(all["meterError"], 12)
+	s.Len(all["userAssignmentState"], 10)
+	s.Len(all["ocppState"], 8)
+	s.Len(all["rfidState"], 4)
+	s.Len(all["timeStatus"], 4)
+	s.Len(all["transactionType"], 10)
+	s.Len(all["iso5118State"], 2)
+	s.Len(all["chargePointAssignment"], 2)
+	s.Len(all["unit"], 4)
+	s.Len(all["currentType"], 2)
+}
+
+type descriptionEntry struct {
+	value   string
+	valid   bool
+	english string
+	german  string
+}
+
+func entries[T ~string](d descriptions[T], isValid func(T) bool) []descriptionEntry {
+	result := []descriptionEntry{}
+	for _, entry := range d {
+		result = append(result, descriptionEntry{
+			value:   string(entry.value),
+			valid:   isValid(entry.value),
+			english: entry.english,
+			german:  entry.german,
+		})
+	}
+
+	return result
+}
+
+func TestDescriptions(t *testing.T) {
+	suite.Run(t, new(descriptionsTestSuite))
+}
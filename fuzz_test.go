@@ -0,0 +1,204 @@
+package ocmf_go
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+// messageSeeds returns the example message and the messages of the conformance corpus.
+func messageSeeds(f *testing.F) []string {
+	seeds := []string{examplePayload}
+
+	files, err := filepath.Glob(filepath.Join("conformance", "corpus", "vectors", "*.json"))
+	require.NoError(f, err)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(f, err)
+
+		vector := struct {
+			Message string `json:"message"`
+		}{}
+		require.NoError(f, json.Unmarshal(data, &vector))
+		seeds = append(seeds, vector.Message)
+	}
+
+	return seeds
+}
+
+func FuzzParseOcmfMessageFromString(f *testing.F) {
+	for _, seed := range messageSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		payload, signature, err := parseOcmfMessageFromString(data)
+		if err != nil {
+			require.Nil(t, payload)
+			require.Nil(t, signature)
+			return
+		}
+
+		require.NotNil(t, payload)
+		require.NotNil(t, signature)
+
+		// The raw sections are kept, so the message is reproduced exactly
+		message, err := ParseMessage(data)
+		require.NoError(t, err)
+		require.Equal(t, data, message.String())
+
+		err = payload.Validate()
+		require.Equal(t, err == nil, len(FieldErrors(err)) == 0)
+	})
+}
+
+func FuzzPayloadSectionValidate(f *testing.F) {
+	for _, seed := range messageSeeds(f) {
+		if message, err := ParseMessage(seed); err == nil {
+			f.Add(message.RawPayload)
+		}
+	}
+	f.Add([]byte(`{"PG":"T1","MS":"1","IT":"RFID_NONE","RD":[{"TM":"2018-07-24T13:22:04,000+0200 S","RV":1,"RU":"kWh","ST":"G"}]}`))
+	f.Add([]byte(`{"RD":[{},{"TM":null}],"IF":["a","b","c","d","e"]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		payload := PayloadSection{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return
+		}
+
+		err := payload.Validate()
+		fieldErrors := FieldErrors(err)
+		require.Equal(t, err == nil, len(fieldErrors) == 0)
+		require.Len(t, LocalizedFieldErrors(err, LanguageGerman), len(fieldErrors))
+
+		for _, fieldError := range fieldErrors {
+			require.NotEmpty(t, fieldError.Field)
+			require.NotEmpty(t, fieldError.Message)
+		}
+
+		if err != nil {
+			return
+		}
+
+		// Valid payloads stay valid when they are encoded by the library
+		encoded, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		decoded := PayloadSection{}
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		require.NoError(t, decoded.Validate())
+	})
+}
+
+func FuzzSignatureVerify(f *testing.F) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(f, err)
+
+	for _, seed := range messageSeeds(f) {
+		f.Add([]byte(seed), uint(0), byte(1))
+	}
+	f.Add([]byte{}, uint(3), byte(0))
+
+	f.Fuzz(func(t *testing.T, payload []byte, index uint, mask byte) {
+		signature := NewDefaultSignature()
+		require.NoError(t, signature.SignBytes(payload, privateKey))
+
+		valid, err := signature.VerifyBytes(payload, &privateKey.PublicKey)
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		// Any change of the signed bytes must be detected
+		mutated := slices.Clone(payload)
+		if mask == 0 {
+			mask = 1
+		}
+
+		if len(mutated) == 0 {
+			mutated = append(mutated, mask)
+		} else {
+			mutated[index%uint(len(mutated))] ^= mask
+		}
+
+		valid, _ = signature.VerifyBytes(mutated, &privateKey.PublicKey)
+		require.False(t, valid)
+	})
+}
+
+func FuzzSignatureVerify_arbitrarySignature(f *testing.F) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(f, err)
+
+	f.Add("3045022100", string(SignatureEncodingHex))
+	f.Add("MEUCIQ==", string(SignatureEncodingBase64))
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, data, encoding string) {
+		signature := Signature{
+			Algorithm: SignatureAlgorithmECDSAsecp256r1SHA256,
+			Encoding:  SignatureEncoding(encoding),
+			MimeType:  SignatureMimeTypeDer,
+			Data:      data,
+		}
+
+		valid, _ := signature.VerifyBytes([]byte(examplePayload), &privateKey.PublicKey)
+		require.False(t, valid)
+	})
+}
+
+var (
+	fuzzTransactions = []TransactionType{"", TransactionBegin, TransactionCharging, TransactionEnd, TransactionTerminatedAbort}
+	fuzzStatuses     = []MeterError{MeterOk, MeterTimeout, MeterManipulated, MeterError("Z")}
+)
+
+func FuzzBuilderRoundTrip(f *testing.F) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(f, err)
+
+	f.Add("exampleSerial123", uint32(1), "Tarif 1", 2935.6, uint8(1), uint8(0))
+	f.Add("BQ27400330016", uint32(12345), "0,49 €/kWh | 0,10 €/min", 0.001, uint8(3), uint8(2))
+	f.Add("", uint32(0), "", 0.0, uint8(0), uint8(3))
+
+	f.Fuzz(func(t *testing.T, meterSerial string, counter uint32, tariff string, value float64, transaction, status uint8) {
+		// Invalid UTF-8 is replaced when the payload is encoded, so it cannot round-trip
+		if !utf8.ValidString(meterSerial) || !utf8.ValidString(tariff) {
+			t.Skip()
+		}
+
+		message, err := NewBuilder(privateKey).
+			WithPagination(fmt.Sprintf("T%d", counter)).
+			WithMeterSerial(meterSerial).
+			WithIdentificationType(string(RfidNone)).
+			WithTariffText(tariff).
+			AddReading(Reading{
+				Time:         "2018-07-24T13:22:04,000+0200 S",
+				Transaction:  string(fuzzTransactions[int(transaction)%len(fuzzTransactions)]),
+				ReadingValue: value,
+				ReadingUnit:  string(UnitskWh),
+				Status:       string(fuzzStatuses[int(status)%len(fuzzStatuses)]),
+			}).
+			Build()
+		if err != nil {
+			// Rejected by the validation
+			return
+		}
+
+		parsed, err := NewParser(
+			WithAutomaticValidation(),
+			WithAutomaticSignatureVerification(&privateKey.PublicKey),
+		).ParseOcmfMessageFromString(message.String()).GetMessage()
+		require.NoError(t, err)
+		require.Equal(t, message.Payload, parsed.Payload)
+		require.Equal(t, message.Signature, parsed.Signature)
+	})
+}
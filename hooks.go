@@ -0,0 +1,126 @@
+package ocmf_go
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FailureReason classifies why a message could not be parsed, verified or built. It is meant to be used as a metric label.
+type FailureReason string
+
+const (
+	// ReasonFormat is used for messages that cannot be decoded.
+	ReasonFormat = FailureReason("format")
+	// ReasonValidation is used for payload or signature sections that do not pass the validation.
+	ReasonValidation = FailureReason("validation")
+
+	// ReasonSignatureMismatch is used for signatures that do not match the payload.
+	ReasonSignatureMismatch = FailureReason("signature_mismatch")
+	ReasonKeyNotFound       = FailureReason("key_not_found")
+	ReasonKeyMismatch       = FailureReason("key_mismatch")
+	ReasonPublicKeyMissing  = FailureReason("public_key_missing")
+	ReasonAlgorithmMismatch = FailureReason("algorithm_mismatch")
+	ReasonCancelled         = FailureReason("cancelled")
+	// ReasonError is used for all other errors, e.g. signatures that cannot be decoded.
+	ReasonError = FailureReason("error")
+
+	ReasonConfiguration = FailureReason("configuration")
+	ReasonPagination    = FailureReason("pagination")
+	ReasonSigning       = FailureReason("signing")
+)
+
+// Metrics receives the measurements of the Parser and Builder. Implementations must be safe for concurrent use.
+// Embed NoopMetrics to implement only some of the methods.
+type Metrics interface {
+	// MessageParsed is called for every message passed to a parser.
+	MessageParsed()
+	// MessageInvalid is called when a message cannot be decoded or does not pass the validation.
+	MessageInvalid(reason FailureReason)
+	// VerificationFailed is called when the signature of a message is invalid or cannot be verified.
+	VerificationFailed(reason FailureReason)
+	// VerificationDuration is called after every verification, including the key resolution.
+	VerificationDuration(duration time.Duration)
+	MessageBuilt()
+	BuildFailed(reason FailureReason)
+}
+
+// NoopMetrics discards all measurements.
+type NoopMetrics struct{}
+
+func (NoopMetrics) MessageParsed()                     {}
+func (NoopMetrics) MessageInvalid(FailureReason)       {}
+func (NoopMetrics) VerificationFailed(FailureReason)   {}
+func (NoopMetrics) VerificationDuration(time.Duration) {}
+func (NoopMetrics) MessageBuilt()                      {}
+func (NoopMetrics) BuildFailed(FailureReason)          {}
+
+// hooks holds the optional logger and metrics of a parser or builder.
+// Messages are logged with their meter serial and pagination, but never with the identification data.
+type hooks struct {
+	logger  *slog.Logger
+	metrics Metrics
+}
+
+func (h hooks) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if h.logger != nil {
+		h.logger.Log(ctx, level, msg, args...)
+	}
+}
+
+func (h hooks) meter() Metrics {
+	if h.metrics == nil {
+		return NoopMetrics{}
+	}
+
+	return h.metrics
+}
+
+// verificationFailureReason classifies the error of a verification. A nil error means the signature did not match.
+func verificationFailureReason(err error) FailureReason {
+	switch {
+	case err == nil:
+		return ReasonSignatureMismatch
+	case errors.Is(err, ErrKeyNotFound):
+		return ReasonKeyNotFound
+	case errors.Is(err, ErrPublicKeyMismatch):
+		return ReasonKeyMismatch
+	case errors.Is(err, ErrPublicKeyMissing):
+		return ReasonPublicKeyMissing
+	case errors.Is(err, ErrKeyAlgorithmMismatch), errors.Is(err, ErrUnsupportedAlgorithm):
+		return ReasonAlgorithmMismatch
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ReasonCancelled
+	default:
+		return ReasonError
+	}
+}
+
+// observeVerification records the result of a verification that started at start.
+func (h hooks) observeVerification(ctx context.Context, start time.Time, payload *PayloadSection, valid bool, err error) {
+	duration := time.Since(start)
+	h.meter().VerificationDuration(duration)
+
+	if valid && err == nil {
+		h.log(ctx, slog.LevelDebug, "OCMF signature verified",
+			"meterSerial", payload.MeterSerial, "pagination", payload.Pagination, "duration", duration)
+		return
+	}
+
+	reason := verificationFailureReason(err)
+	h.meter().VerificationFailed(reason)
+
+	args := []any{"meterSerial", payload.MeterSerial, "pagination", payload.Pagination, "reason", reason, "duration", duration}
+	if err != nil {
+		args = append(args, "error", err)
+	}
+
+	h.log(ctx, slog.LevelWarn, "OCMF signature verification failed", args...)
+}
+
+func (h hooks) observeInvalid(ctx context.Context, reason FailureReason, err error) {
+	h.meter().MessageInvalid(reason)
+	h.log(ctx, slog.LevelWarn, "invalid OCMF message", "reason", reason, "error", err)
+}
@@ -0,0 +1,249 @@
+package ocmf_go
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingMetrics struct {
+	mu                 sync.Mutex
+	parsed             int
+	invalid            []FailureReason
+	verificationFailed []FailureReason
+	durations          []time.Duration
+	built              int
+	buildFailed        []FailureReason
+}
+
+func (m *recordingMetrics) MessageParsed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parsed++
+}
+
+func (m *recordingMetrics) MessageInvalid(reason FailureReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.invalid = append(m.invalid, reason)
+}
+
+func (m *recordingMetrics) VerificationFailed(reason FailureReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verificationFailed = append(m.verificationFailed, reason)
+}
+
+func (m *recordingMetrics) VerificationDuration(duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations = append(m.durations, duration)
+}
+
+func (m *recordingMetrics) MessageBuilt() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.built++
+}
+
+func (m *recordingMetrics) BuildFailed(reason FailureReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buildFailed = append(m.buildFailed, reason)
+}
+
+type hooksTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	message    *Message
+	metrics    *recordingMetrics
+	logs       *bytes.Buffer
+	logger     *slog.Logger
+}
+
+func (s *hooksTestSuite) SetupTest() {
+	privateKey, err := GenerateKey(SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.message, err = NewBuilder(privateKey).
+		WithPagination("T1").
+		WithMeterSerial("meter1").
+		WithIdentificationType(string(RfidPlain)).
+		WithIdentificationData("0A1B2C3D4E5F6789").
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			Transaction:  string(TransactionBegin),
+			ReadingValue: 10,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+
+	s.metrics = &recordingMetrics{}
+	s.logs = &bytes.Buffer{}
+	s.logger = slog.New(slog.NewTextHandler(s.logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func (s *hooksTestSuite) TestParser() {
+	otherKey, err := GenerateKey(SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+
+	p384Key, err := GenerateKey(SignatureAlgorithmECDSAsecp384r1SHA256)
+	s.Require().NoError(err)
+
+	registry := NewKeyRegistry()
+	registry.Register("meter1", &s.privateKey.PublicKey)
+
+	tests := []struct {
+		name               string
+		opts               []Opt
+		message            string
+		expectedInvalid    []FailureReason
+		expectedFailed     []FailureReason
+		expectedDurations  int
+		expectedLogMessage string
+	}{
+		{
+			name:               "Valid",
+			opts:               []Opt{WithAutomaticValidation(), WithAutomaticSignatureVerification(&s.privateKey.PublicKey)},
+			message:            s.message.String(),
+			expectedDurations:  1,
+			expectedLogMessage: "OCMF signature verified",
+		},
+		{
+			name:               "Invalid format",
+			message:            "OCMF|{}",
+			expectedInvalid:    []FailureReason{ReasonFormat},
+			expectedLogMessage: "invalid OCMF message",
+		},
+		{
+			name:               "Invalid payload",
+			opts:               []Opt{WithAutomaticValidation()},
+			message:            strings.Replace(s.message.String(), `"PG":"T1"`, `"PG":"X1"`, 1),
+			expectedInvalid:    []FailureReason{ReasonValidation},
+			expectedLogMessage: "invalid OCMF message",
+		},
+		{
+			name:               "Signature mismatch",
+			opts:               []Opt{WithAutomaticSignatureVerification(&otherKey.PublicKey)},
+			message:            s.message.String(),
+			expectedFailed:     []FailureReason{ReasonSignatureMismatch},
+			expectedDurations:  1,
+			expectedLogMessage: "OCMF signature verification failed",
+		},
+		{
+			name:               "Algorithm mismatch",
+			opts:               []Opt{WithAutomaticSignatureVerification(&p384Key.PublicKey)},
+			message:            s.message.String(),
+			expectedFailed:     []FailureReason{ReasonAlgorithmMismatch},
+			expectedDurations:  1,
+			expectedLogMessage: "OCMF signature verification failed",
+		},
+		{
+			name:               "Key not found",
+			opts:               []Opt{WithKeyResolver(NewKeyRegistry())},
+			message:            s.message.String(),
+			expectedFailed:     []FailureReason{ReasonKeyNotFound},
+			expectedDurations:  1,
+			expectedLogMessage: "OCMF signature verification failed",
+		},
+		{
+			name:               "Key mismatch",
+			opts:               []Opt{WithKeyResolver(registry), WithAutomaticSignatureVerification(&otherKey.PublicKey)},
+			message:            s.message.String(),
+			expectedFailed:     []FailureReason{ReasonKeyMismatch},
+			expectedDurations:  1,
+			expectedLogMessage: "OCMF signature verification failed",
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.SetupTest()
+
+			opts := append([]Opt{WithLogger(s.logger), WithMetrics(s.metrics)}, tt.opts...)
+			_, _ = NewParser(opts...).ParseOcmfMessageFromString(tt.message).GetMessage()
+
+			s.Equal(1, s.metrics.parsed)
+			s.Equal(tt.expectedInvalid, s.metrics.invalid)
+			s.Equal(tt.expectedFailed, s.metrics.verificationFailed)
+			s.Len(s.metrics.durations, tt.expectedDurations)
+			s.Contains(s.logs.String(), tt.expectedLogMessage)
+			s.NotContains(s.logs.String(), "0A1B2C3D4E5F6789")
+		})
+	}
+}
+
+func (s *hooksTestSuite) TestParserReport() {
+	parser := NewParser(WithMetrics(s.metrics), WithKeyResolver(NewKeyRegistry()))
+
+	report, err := parser.ParseMessage(*s.message).GetReport(context.Background())
+	s.Require().NoError(err)
+	s.Equal(VerdictError, report.Verdict)
+
+	report, err = NewParser(WithMetrics(s.metrics), WithAutomaticSignatureVerification(&s.privateKey.PublicKey)).
+		ParseMessage(*s.message).
+		GetReport(context.Background())
+	s.Require().NoError(err)
+	s.True(report.Valid())
+
+	s.Equal(2, s.metrics.parsed)
+	s.Equal([]FailureReason{ReasonKeyNotFound}, s.metrics.verificationFailed)
+	s.Len(s.metrics.durations, 2)
+}
+
+func (s *hooksTestSuite) TestBuilder() {
+	template := NewBuilder(s.privateKey, WithBuilderLogger(s.logger), WithBuilderMetrics(s.metrics)).
+		WithMeterSerial("meter1").
+		Template()
+
+	_, err := template.NewBuilder().
+		WithPagination("T2").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(s.message.Payload.Readings[0]).
+		Build()
+	s.Require().NoError(err)
+
+	// No readings
+	_, err = template.NewBuilder().WithPagination("T3").Build()
+	s.Error(err)
+
+	_, err = NewBuilder(s.privateKey, WithBuilderMetrics(s.metrics), WithSignatureAlgorithm("ECDSA-unknown")).Build()
+	s.Error(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = template.NewBuilder().
+		WithPagination("T4").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(s.message.Payload.Readings[0]).
+		BuildContext(ctx)
+	s.Error(err)
+
+	s.Equal(1, s.metrics.built)
+	s.Equal([]FailureReason{ReasonValidation, ReasonConfiguration, ReasonSigning}, s.metrics.buildFailed)
+	s.Contains(s.logs.String(), "OCMF message built")
+	s.Contains(s.logs.String(), "failed to build OCMF message")
+}
+
+func (s *hooksTestSuite) TestWithoutHooks() {
+	// Neither a logger nor metrics are required
+	_, err := NewParser(WithAutomaticSignatureVerification(&s.privateKey.PublicKey)).ParseMessage(*s.message).GetMessage()
+	s.NoError(err)
+
+	_, err = NewParser().ParseOcmfMessageFromString("OCMF").GetMessage()
+	s.Error(err)
+}
+
+func TestHooks(t *testing.T) {
+	suite.Run(t, new(hooksTestSuite))
+}
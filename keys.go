@@ -0,0 +1,232 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	ErrKeyNotFound       = errors.New("public key not found")
+	ErrPublicKeyMismatch = errors.New("public key does not match the registered key")
+	ErrPublicKeyMissing  = errors.New("public key is missing")
+)
+
+// ParsePublicKey decodes an ECDSA public key in any of the encodings commonly used for meter keys:
+// PEM, DER (SubjectPublicKeyInfo) or an uncompressed curve point, the latter two either raw, hex or base64 encoded.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, errors.Wrap(ErrInvalidPublicKey, "empty key")
+	}
+
+	if block, _ := pem.Decode([]byte(trimmed)); block != nil {
+		return parsePublicKeyBytes(block.Bytes)
+	}
+
+	if decoded, err := hex.DecodeString(trimmed); err == nil {
+		return parsePublicKeyBytes(decoded)
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(trimmed); err == nil {
+		return parsePublicKeyBytes(decoded)
+	}
+
+	return parsePublicKeyBytes(data)
+}
+
+func parsePublicKeyBytes(data []byte) (*ecdsa.PublicKey, error) {
+	if key, err := x509.ParsePKIXPublicKey(data); err == nil {
+		publicKey, isECDSA := key.(*ecdsa.PublicKey)
+		if !isECDSA {
+			return nil, errors.Wrap(ErrInvalidPublicKey, "not an ECDSA key")
+		}
+
+		return publicKey, nil
+	}
+
+	// Uncompressed point: 0x04 || X || Y
+	var curve elliptic.Curve
+	var ecdhCurve ecdh.Curve
+	switch len(data) {
+	case 65:
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case 97:
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	default:
+		return nil, errors.Wrap(ErrInvalidPublicKey, "unsupported key format")
+	}
+
+	// Checks that the point is on the curve
+	if _, err := ecdhCurve.NewPublicKey(data); err != nil {
+		return nil, errors.Wrap(ErrInvalidPublicKey, err.Error())
+	}
+
+	size := (len(data) - 1) / 2
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(data[1 : 1+size]),
+		Y:     new(big.Int).SetBytes(data[1+size:]),
+	}, nil
+}
+
+// MarshalPublicKey encodes the public key as DER SubjectPublicKeyInfo.
+func MarshalPublicKey(publicKey *ecdsa.PublicKey) ([]byte, error) {
+	if publicKey == nil {
+		return nil, errors.New("public key is required")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal public key")
+	}
+
+	return der, nil
+}
+
+// KeyFingerprint returns the hex encoded SHA-256 hash of the DER encoded public key.
+func KeyFingerprint(publicKey *ecdsa.PublicKey) (string, error) {
+	der, err := MarshalPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// GenerateKey creates a new private key on the curve of the signature algorithm.
+func GenerateKey(algorithm SignatureAlgorithm) (*ecdsa.PrivateKey, error) {
+	curve, err := curveForAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+
+	return privateKey, nil
+}
+
+// ParsePrivateKey decodes an ECDSA private key in PEM or DER (SEC 1 or PKCS #8) format, the DER either raw, hex or base64 encoded.
+func ParsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	trimmed := strings.TrimSpace(string(data))
+
+	der := data
+	if block, _ := pem.Decode([]byte(trimmed)); block != nil {
+		der = block.Bytes
+	} else if decoded, err := hex.DecodeString(trimmed); err == nil {
+		der = decoded
+	} else if decoded, err := base64.StdEncoding.DecodeString(trimmed); err == nil {
+		der = decoded
+	}
+
+	if privateKey, err := x509.ParseECPrivateKey(der); err == nil {
+		return privateKey, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, "unsupported key format")
+	}
+
+	privateKey, isECDSA := key.(*ecdsa.PrivateKey)
+	if !isECDSA {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, "not an ECDSA key")
+	}
+
+	return privateKey, nil
+}
+
+// KeyResolver looks up the public key of a meter.
+type KeyResolver interface {
+	ResolvePublicKey(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error)
+}
+
+type KeyResolverFunc func(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error)
+
+func (f KeyResolverFunc) ResolvePublicKey(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error) {
+	return f(ctx, meterSerial)
+}
+
+// KeyRegistry is an in-memory KeyResolver. It is safe for concurrent use.
+type KeyRegistry struct {
+	mu   sync.RWMutex
+	keys map[string]*ecdsa.PublicKey
+}
+
+func NewKeyRegistry() *KeyRegistry {
+	return &KeyRegistry{
+		keys: make(map[string]*ecdsa.PublicKey),
+	}
+}
+
+func (r *KeyRegistry) Register(meterSerial string, publicKey *ecdsa.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[meterSerial] = publicKey
+}
+
+func (r *KeyRegistry) Remove(meterSerial string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, meterSerial)
+}
+
+func (r *KeyRegistry) ResolvePublicKey(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	publicKey, found := r.keys[meterSerial]
+	if !found {
+		return nil, errors.Wrapf(ErrKeyNotFound, "meter %s", meterSerial)
+	}
+
+	return publicKey, nil
+}
+
+// SelectPublicKey picks the key a message of the meter should be verified with.
+// Keys transported with the message cannot be trusted on their own: if a resolver is given,
+// the registered key is used and the embedded key must match it. Otherwise, the embedded key is used.
+func SelectPublicKey(ctx context.Context, resolver KeyResolver, meterSerial string, embeddedKey *ecdsa.PublicKey) (*ecdsa.PublicKey, error) {
+	if resolver == nil {
+		if embeddedKey == nil {
+			return nil, ErrPublicKeyMissing
+		}
+
+		return embeddedKey, nil
+	}
+
+	publicKey, err := resolver.ResolvePublicKey(ctx, meterSerial)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve public key")
+	}
+
+	if embeddedKey != nil && !embeddedKey.Equal(publicKey) {
+		return nil, errors.Wrapf(ErrPublicKeyMismatch, "meter %s", meterSerial)
+	}
+
+	return publicKey, nil
+}
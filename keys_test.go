@@ -0,0 +1,233 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type keysTestSuite struct {
+	suite.Suite
+}
+
+func (s *keysTestSuite) TestParsePublicKey() {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		s.Require().NoError(err)
+		publicKey := &privateKey.PublicKey
+
+		der, err := MarshalPublicKey(publicKey)
+		s.Require().NoError(err)
+
+		point, err := publicKey.ECDH()
+		s.Require().NoError(err)
+
+		encodings := map[string][]byte{
+			"PEM":            pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+			"DER":            der,
+			"Hex DER":        []byte(hex.EncodeToString(der)),
+			"Padded hex DER": []byte(" " + hex.EncodeToString(der) + "\n"),
+			"Base64 DER":     []byte(base64.StdEncoding.EncodeToString(der)),
+			"Raw point":      point.Bytes(),
+			"Hex point":      []byte(hex.EncodeToString(point.Bytes())),
+		}
+
+		for name, data := range encodings {
+			s.T().Run(curve.Params().Name+" "+name, func(t *testing.T) {
+				parsed, err := ParsePublicKey(data)
+				s.Require().NoError(err)
+				s.True(publicKey.Equal(parsed))
+			})
+		}
+	}
+}
+
+func (s *keysTestSuite) TestParsePublicKey_invalid() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	s.Require().NoError(err)
+	rsaDer, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	s.Require().NoError(err)
+
+	invalidPoint := make([]byte, 65)
+	invalidPoint[0] = 4
+
+	for name, data := range map[string][]byte{
+		"Empty":         {},
+		"Garbage":       []byte("not a key"),
+		"RSA key":       rsaDer,
+		"Invalid point": invalidPoint,
+	} {
+		s.T().Run(name, func(t *testing.T) {
+			_, err := ParsePublicKey(data)
+			s.ErrorIs(err, ErrInvalidPublicKey)
+		})
+	}
+}
+
+func (s *keysTestSuite) TestGenerateKey() {
+	privateKey, err := GenerateKey(SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+	s.Equal(elliptic.P256(), privateKey.Curve)
+
+	privateKey, err = GenerateKey(SignatureAlgorithmECDSAsecp384r1SHA256)
+	s.Require().NoError(err)
+	s.Equal(elliptic.P384(), privateKey.Curve)
+
+	_, err = GenerateKey(SignatureAlgorithmECDSAbrainpool256r11SHA256)
+	s.ErrorIs(err, ErrUnsupportedAlgorithm)
+}
+
+func (s *keysTestSuite) TestKeyFingerprint() {
+	privateKey, err := GenerateKey(SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+
+	der, err := MarshalPublicKey(&privateKey.PublicKey)
+	s.Require().NoError(err)
+
+	// The same key in another encoding has the same fingerprint
+	parsed, err := ParsePublicKey([]byte(hex.EncodeToString(der)))
+	s.Require().NoError(err)
+
+	fingerprint, err := KeyFingerprint(&privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.Len(fingerprint, 64)
+
+	parsedFingerprint, err := KeyFingerprint(parsed)
+	s.Require().NoError(err)
+	s.Equal(fingerprint, parsedFingerprint)
+
+	_, err = KeyFingerprint(nil)
+	s.Error(err)
+}
+
+func (s *keysTestSuite) TestParsePrivateKey() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	sec1, err := x509.MarshalECPrivateKey(privateKey)
+	s.Require().NoError(err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	s.Require().NoError(err)
+
+	encodings := map[string][]byte{
+		"SEC1 PEM":    pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+		"PKCS8 PEM":   pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		"SEC1 DER":    sec1,
+		"PKCS8 hex":   []byte(hex.EncodeToString(pkcs8)),
+		"SEC1 base64": []byte(base64.StdEncoding.EncodeToString(sec1)),
+	}
+
+	for name, data := range encodings {
+		s.T().Run(name, func(t *testing.T) {
+			parsed, err := ParsePrivateKey(data)
+			s.Require().NoError(err)
+			s.True(privateKey.Equal(parsed))
+		})
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	s.Require().NoError(err)
+	rsaDer, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	s.Require().NoError(err)
+
+	_, err = ParsePrivateKey(rsaDer)
+	s.ErrorIs(err, ErrInvalidPrivateKey)
+
+	_, err = ParsePrivateKey([]byte("garbage"))
+	s.ErrorIs(err, ErrInvalidPrivateKey)
+}
+
+func (s *keysTestSuite) TestKeyRegistry() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	registry := NewKeyRegistry()
+	registry.Register("meter1", &privateKey.PublicKey)
+
+	publicKey, err := registry.ResolvePublicKey(context.Background(), "meter1")
+	s.Require().NoError(err)
+	s.Equal(&privateKey.PublicKey, publicKey)
+
+	_, err = registry.ResolvePublicKey(context.Background(), "meter2")
+	s.ErrorIs(err, ErrKeyNotFound)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = registry.ResolvePublicKey(ctx, "meter1")
+	s.ErrorIs(err, context.Canceled)
+
+	registry.Remove("meter1")
+	_, err = registry.ResolvePublicKey(context.Background(), "meter1")
+	s.ErrorIs(err, ErrKeyNotFound)
+}
+
+func (s *keysTestSuite) TestSelectPublicKey() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	registry := NewKeyRegistry()
+	registry.Register("meter1", &privateKey.PublicKey)
+
+	tests := []struct {
+		name        string
+		resolver    KeyResolver
+		embeddedKey *ecdsa.PublicKey
+		expected    *ecdsa.PublicKey
+		error       error
+	}{
+		{
+			name:        "Embedded key only",
+			embeddedKey: &otherKey.PublicKey,
+			expected:    &otherKey.PublicKey,
+		},
+		{
+			name:     "Resolved key only",
+			resolver: registry,
+			expected: &privateKey.PublicKey,
+		},
+		{
+			name:        "Matching keys",
+			resolver:    registry,
+			embeddedKey: &privateKey.PublicKey,
+			expected:    &privateKey.PublicKey,
+		},
+		{
+			name:        "Mismatching keys",
+			resolver:    registry,
+			embeddedKey: &otherKey.PublicKey,
+			error:       ErrPublicKeyMismatch,
+		},
+		{
+			name:  "No key",
+			error: ErrPublicKeyMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			publicKey, err := SelectPublicKey(context.Background(), tt.resolver, "meter1", tt.embeddedKey)
+			if tt.error != nil {
+				s.ErrorIs(err, tt.error)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Equal(tt.expected, publicKey)
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	suite.Run(t, new(keysTestSuite))
+}
@@ -0,0 +1,114 @@
+package merkle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"strconv"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+const anchorHeader = "OCMF-MERKLE"
+
+var ErrInvalidSignature = errors.New("anchor signature is invalid")
+
+// Anchor is the signed root of a batch, e.g. of the messages of one day.
+// It is published or handed to auditors, who can then verify inclusion proofs against it.
+type Anchor struct {
+	Root      string         `json:"root"`
+	Size      int            `json:"size"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Signature ocmf.Signature `json:"signature"`
+}
+
+// signedBytes returns the bytes covered by the signature of the anchor.
+func (a Anchor) signedBytes() []byte {
+	data := make([]byte, 0, 128)
+	data = append(data, anchorHeader...)
+	data = append(data, '|')
+	data = append(data, a.Root...)
+	data = append(data, '|')
+	data = strconv.AppendInt(data, int64(a.Size), 10)
+	data = append(data, '|')
+	data = append(data, a.CreatedAt.UTC().Format(time.RFC3339Nano)...)
+	return data
+}
+
+// Sign signs the root of the tree with the signer, the same way the payload of a message is signed.
+func (t *Tree) Sign(ctx context.Context, signer ocmf.Signer) (*Anchor, error) {
+	if signer == nil || signer.Public() == nil {
+		return nil, errors.New("signer is required")
+	}
+
+	anchor := &Anchor{
+		Root:      t.Root(),
+		Size:      t.Size(),
+		CreatedAt: time.Now().UTC(),
+		Signature: *ocmf.NewDefaultSignature(),
+	}
+
+	if signer.Public().Curve.Params().Name == "P-384" {
+		anchor.Signature.Algorithm = ocmf.SignatureAlgorithmECDSAsecp384r1SHA256
+	}
+
+	if err := anchor.Signature.SignBytesContext(ctx, anchor.signedBytes(), signer); err != nil {
+		return nil, errors.Wrap(err, "failed to sign root")
+	}
+
+	return anchor, nil
+}
+
+// Verify checks the signature of the anchor.
+func (a Anchor) Verify(publicKey *ecdsa.PublicKey) error {
+	valid, err := a.Signature.VerifyBytes(a.signedBytes(), publicKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify anchor")
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Evidence is everything an auditor needs to check offline that a message was part of an anchored batch.
+type Evidence struct {
+	Message string `json:"message"`
+	Proof   Proof  `json:"proof"`
+	Anchor  Anchor `json:"anchor"`
+}
+
+// Evidence returns the evidence for the message at index. The anchor must have been created for the tree.
+func (t *Tree) Evidence(index int, anchor Anchor) (*Evidence, error) {
+	if anchor.Root != t.Root() || anchor.Size != t.Size() {
+		return nil, errors.New("anchor does not belong to the tree")
+	}
+
+	proof, err := t.Proof(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Evidence{
+		Message: string(t.messages[index]),
+		Proof:   *proof,
+		Anchor:  anchor,
+	}, nil
+}
+
+// Verify checks the signature of the anchor with the public key of the anchoring party and the inclusion of the message.
+// The signature of the message itself is not checked, use the key of the meter for that.
+func (e Evidence) Verify(publicKey *ecdsa.PublicKey) error {
+	if err := e.Anchor.Verify(publicKey); err != nil {
+		return err
+	}
+
+	if e.Proof.Size != e.Anchor.Size {
+		return errors.Wrapf(ErrInvalidProof, "proof is for %d messages, the anchor for %d", e.Proof.Size, e.Anchor.Size)
+	}
+
+	return VerifyProof([]byte(e.Message), e.Proof, e.Anchor.Root)
+}
@@ -0,0 +1,164 @@
+package merkle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type anchorTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	signer     ocmf.Signer
+	tree       *Tree
+}
+
+func (s *anchorTestSuite) SetupTest() {
+	privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.signer, err = ocmf.NewPrivateKeySigner(privateKey)
+	s.Require().NoError(err)
+
+	s.tree, err = NewTree(batch(5))
+	s.Require().NoError(err)
+}
+
+func (s *anchorTestSuite) TestSign() {
+	anchor, err := s.tree.Sign(context.Background(), s.signer)
+	s.Require().NoError(err)
+	s.Equal(s.tree.Root(), anchor.Root)
+	s.Equal(5, anchor.Size)
+	s.WithinDuration(time.Now(), anchor.CreatedAt, time.Minute)
+	s.Equal(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256, anchor.Signature.Algorithm)
+	s.NoError(anchor.Verify(&s.privateKey.PublicKey))
+
+	// The anchor survives encoding
+	data, err := json.Marshal(anchor)
+	s.Require().NoError(err)
+
+	var decoded Anchor
+	s.Require().NoError(json.Unmarshal(data, &decoded))
+	s.NoError(decoded.Verify(&s.privateKey.PublicKey))
+
+	otherKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+	s.ErrorIs(anchor.Verify(&otherKey.PublicKey), ErrInvalidSignature)
+
+	tests := []struct {
+		name   string
+		modify func(anchor *Anchor)
+	}{
+		{
+			name: "Root",
+			modify: func(anchor *Anchor) {
+				anchor.Root = hex.EncodeToString(LeafHash([]byte("OCMF")))
+			},
+		},
+		{
+			name: "Size",
+			modify: func(anchor *Anchor) {
+				anchor.Size++
+			},
+		},
+		{
+			name: "Creation time",
+			modify: func(anchor *Anchor) {
+				anchor.CreatedAt = anchor.CreatedAt.Add(time.Nanosecond)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			modified := *anchor
+			tt.modify(&modified)
+			s.ErrorIs(modified.Verify(&s.privateKey.PublicKey), ErrInvalidSignature)
+		})
+	}
+}
+
+func (s *anchorTestSuite) TestSignP384() {
+	privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp384r1SHA256)
+	s.Require().NoError(err)
+
+	signer, err := ocmf.NewPrivateKeySigner(privateKey)
+	s.Require().NoError(err)
+
+	anchor, err := s.tree.Sign(context.Background(), signer)
+	s.Require().NoError(err)
+	s.Equal(ocmf.SignatureAlgorithmECDSAsecp384r1SHA256, anchor.Signature.Algorithm)
+	s.NoError(anchor.Verify(&privateKey.PublicKey))
+
+	// The key must match the algorithm
+	s.ErrorIs(anchor.Verify(&s.privateKey.PublicKey), ocmf.ErrKeyAlgorithmMismatch)
+}
+
+func (s *anchorTestSuite) TestSignCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.tree.Sign(ctx, s.signer)
+	s.ErrorIs(err, context.Canceled)
+
+	_, err = s.tree.Sign(context.Background(), nil)
+	s.Error(err)
+}
+
+func (s *anchorTestSuite) TestEvidence() {
+	anchor, err := s.tree.Sign(context.Background(), s.signer)
+	s.Require().NoError(err)
+
+	for index := 0; index < s.tree.Size(); index++ {
+		evidence, err := s.tree.Evidence(index, *anchor)
+		s.Require().NoError(err)
+		s.Equal(string(batch(5)[index]), evidence.Message)
+
+		// Auditors receive the evidence as JSON and only need the public key
+		data, err := json.Marshal(evidence)
+		s.Require().NoError(err)
+
+		var decoded Evidence
+		s.Require().NoError(json.Unmarshal(data, &decoded))
+		s.NoError(decoded.Verify(&s.privateKey.PublicKey))
+	}
+
+	evidence, err := s.tree.Evidence(1, *anchor)
+	s.Require().NoError(err)
+
+	modified := *evidence
+	modified.Message = string(batch(5)[2])
+	s.ErrorIs(modified.Verify(&s.privateKey.PublicKey), ErrInvalidProof)
+
+	modified = *evidence
+	modified.Proof.Size = 4
+	s.ErrorIs(modified.Verify(&s.privateKey.PublicKey), ErrInvalidProof)
+
+	modified = *evidence
+	modified.Anchor.Size = 4
+	s.ErrorIs(modified.Verify(&s.privateKey.PublicKey), ErrInvalidSignature)
+
+	// The anchor of another batch
+	other, err := NewTree(batch(6))
+	s.Require().NoError(err)
+
+	otherAnchor, err := other.Sign(context.Background(), s.signer)
+	s.Require().NoError(err)
+
+	_, err = s.tree.Evidence(1, *otherAnchor)
+	s.Error(err)
+
+	_, err = s.tree.Evidence(5, *anchor)
+	s.Error(err)
+}
+
+func TestAnchor(t *testing.T) {
+	suite.Run(t, new(anchorTestSuite))
+}
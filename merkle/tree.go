@@ -0,0 +1,206 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+// Leaves and nodes are hashed with different prefixes as in RFC 6962, so a node cannot be passed off as a message.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+var (
+	ErrEmptyBatch   = errors.New("batch has no messages")
+	ErrInvalidProof = errors.New("inclusion proof is invalid")
+)
+
+// LeafHash returns the hash of a raw OCMF message as it is stored in the tree.
+func LeafHash(message []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{leafPrefix})
+	hash.Write(message)
+	return hash.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte{nodePrefix})
+	hash.Write(left)
+	hash.Write(right)
+	return hash.Sum(nil)
+}
+
+// Tree is a Merkle tree over a batch of raw OCMF messages, in the order they were added.
+// The tree has the shape defined in RFC 6962, so the root only depends on the messages and their order.
+type Tree struct {
+	messages [][]byte
+	// levels[0] holds the leaf hashes, the last level holds the root.
+	levels [][][]byte
+}
+
+// NewTree builds the tree over the raw messages, exactly as they were signed by the meters.
+func NewTree(messages [][]byte) (*Tree, error) {
+	if len(messages) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	tree := &Tree{messages: make([][]byte, 0, len(messages))}
+
+	leaves := make([][]byte, 0, len(messages))
+	for _, message := range messages {
+		tree.messages = append(tree.messages, bytes.Clone(message))
+		leaves = append(leaves, LeafHash(message))
+	}
+
+	tree.levels = [][][]byte{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				// The last node of an odd level is moved up unchanged
+				next = append(next, level[i])
+				continue
+			}
+
+			next = append(next, nodeHash(level[i], level[i+1]))
+		}
+
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+
+	return tree, nil
+}
+
+// FromMessages builds the tree over the messages. The raw bytes of parsed messages are used, so the
+// messages do not have to be encoded the same way again.
+func FromMessages(messages []ocmf.Message) (*Tree, error) {
+	raw := make([][]byte, 0, len(messages))
+	for _, message := range messages {
+		raw = append(raw, message.Bytes())
+	}
+
+	return NewTree(raw)
+}
+
+// Size returns the number of messages in the tree.
+func (t *Tree) Size() int {
+	return len(t.messages)
+}
+
+// Message returns the raw message at index.
+func (t *Tree) Message(index int) ([]byte, error) {
+	if index < 0 || index >= len(t.messages) {
+		return nil, errors.Errorf("index %d is out of range", index)
+	}
+
+	return bytes.Clone(t.messages[index]), nil
+}
+
+// Root returns the hex encoded root hash of the tree.
+func (t *Tree) Root() string {
+	return hex.EncodeToString(t.levels[len(t.levels)-1][0])
+}
+
+// Proof is the inclusion proof of a single message. It only holds hashes, so it reveals nothing about the
+// other messages of the batch except their number.
+type Proof struct {
+	Index int `json:"index"`
+	Size  int `json:"size"`
+	// Path holds the hex encoded sibling hashes from the leaf up to the root.
+	Path []string `json:"path"`
+}
+
+// Proof returns the inclusion proof of the message at index.
+func (t *Tree) Proof(index int) (*Proof, error) {
+	if index < 0 || index >= len(t.messages) {
+		return nil, errors.Errorf("index %d is out of range", index)
+	}
+
+	proof := &Proof{
+		Index: index,
+		Size:  len(t.messages),
+		Path:  []string{},
+	}
+
+	position := index
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := position ^ 1
+		// A node without a sibling is moved up, so there is nothing to add to the path
+		if sibling < len(level) {
+			proof.Path = append(proof.Path, hex.EncodeToString(level[sibling]))
+		}
+
+		position /= 2
+	}
+
+	return proof, nil
+}
+
+// VerifyProof checks that the raw message is included in the tree with the hex encoded root.
+// It does not need the tree, so auditors can verify proofs offline.
+func VerifyProof(message []byte, proof Proof, root string) error {
+	expected, err := hex.DecodeString(root)
+	if err != nil {
+		return errors.Wrap(ErrInvalidProof, "root is not hex encoded")
+	}
+
+	computed, err := proof.root(LeafHash(message))
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(computed, expected) {
+		return errors.Wrap(ErrInvalidProof, "root does not match")
+	}
+
+	return nil
+}
+
+// root computes the root hash from the leaf hash, following RFC 9162, section 2.1.3.2.
+func (p Proof) root(leaf []byte) ([]byte, error) {
+	if p.Index < 0 || p.Index >= p.Size {
+		return nil, errors.Wrapf(ErrInvalidProof, "index %d is out of range for size %d", p.Index, p.Size)
+	}
+
+	index, last := p.Index, p.Size-1
+	hash := leaf
+
+	for _, encoded := range p.Path {
+		if last == 0 {
+			return nil, errors.Wrap(ErrInvalidProof, "path is too long")
+		}
+
+		sibling, err := hex.DecodeString(encoded)
+		if err != nil || len(sibling) != sha256.Size {
+			return nil, errors.Wrap(ErrInvalidProof, "path contains an invalid hash")
+		}
+
+		if index%2 == 1 || index == last {
+			hash = nodeHash(sibling, hash)
+
+			// Skip the levels on which the node has no sibling
+			for index%2 == 0 && index != 0 {
+				index /= 2
+				last /= 2
+			}
+		} else {
+			hash = nodeHash(hash, sibling)
+		}
+
+		index /= 2
+		last /= 2
+	}
+
+	if last != 0 {
+		return nil, errors.Wrap(ErrInvalidProof, "path is too short")
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,228 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type treeTestSuite struct {
+	suite.Suite
+}
+
+// referenceRoot is the Merkle tree hash as defined in RFC 6962, section 2.1.
+func referenceRoot(messages [][]byte) []byte {
+	if len(messages) == 1 {
+		return LeafHash(messages[0])
+	}
+
+	split := 1
+	for split*2 < len(messages) {
+		split *= 2
+	}
+
+	return nodeHash(referenceRoot(messages[:split]), referenceRoot(messages[split:]))
+}
+
+func batch(size int) [][]byte {
+	messages := make([][]byte, 0, size)
+	for i := 0; i < size; i++ {
+		messages = append(messages, []byte(fmt.Sprintf(`OCMF|{"PG":"T%d"}|{"SD":"%02x"}`, i, i)))
+	}
+
+	return messages
+}
+
+func (s *treeTestSuite) TestRoot() {
+	// Test vector of the certificate transparency implementations
+	leaves := []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+	messages := make([][]byte, 0, len(leaves))
+	for _, leaf := range leaves {
+		message, err := hex.DecodeString(leaf)
+		s.Require().NoError(err)
+		messages = append(messages, message)
+	}
+
+	tree, err := NewTree(messages)
+	s.Require().NoError(err)
+	s.Equal("5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328", tree.Root())
+	s.Equal(8, tree.Size())
+
+	for size := 1; size <= 33; size++ {
+		tree, err := NewTree(batch(size))
+		s.Require().NoError(err)
+		s.Equal(hex.EncodeToString(referenceRoot(batch(size))), tree.Root(), "size %d", size)
+	}
+
+	_, err = NewTree(nil)
+	s.ErrorIs(err, ErrEmptyBatch)
+}
+
+func (s *treeTestSuite) TestProof() {
+	for size := 1; size <= 33; size++ {
+		messages := batch(size)
+		tree, err := NewTree(messages)
+		s.Require().NoError(err)
+
+		for index, message := range messages {
+			proof, err := tree.Proof(index)
+			s.Require().NoError(err)
+			s.Equal(size, proof.Size)
+			s.NoError(VerifyProof(message, *proof, tree.Root()), "size %d, index %d", size, index)
+
+			stored, err := tree.Message(index)
+			s.Require().NoError(err)
+			s.Equal(message, stored)
+		}
+	}
+
+	tree, err := NewTree(batch(3))
+	s.Require().NoError(err)
+
+	_, err = tree.Proof(3)
+	s.Error(err)
+	_, err = tree.Proof(-1)
+	s.Error(err)
+	_, err = tree.Message(3)
+	s.Error(err)
+}
+
+func (s *treeTestSuite) TestInvalidProof() {
+	messages := batch(7)
+	tree, err := NewTree(messages)
+	s.Require().NoError(err)
+
+	proof, err := tree.Proof(4)
+	s.Require().NoError(err)
+	s.Require().Len(proof.Path, 3)
+
+	tests := []struct {
+		name    string
+		message []byte
+		proof   func(proof Proof) Proof
+		root    string
+	}{
+		{
+			name:    "Other message",
+			message: messages[5],
+		},
+		{
+			name:    "Changed message",
+			message: append([]byte{}, append(messages[4], ' ')...),
+		},
+		{
+			name:    "Other index",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Index = 5
+				return proof
+			},
+		},
+		{
+			name:    "Index out of range",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Index = 7
+				return proof
+			},
+		},
+		{
+			name:    "Other size",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Size = 6
+				return proof
+			},
+		},
+		{
+			name:    "Changed path",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Path = []string{proof.Path[1], proof.Path[0], proof.Path[2]}
+				return proof
+			},
+		},
+		{
+			name:    "Path too short",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Path = proof.Path[:2]
+				return proof
+			},
+		},
+		{
+			name:    "Path too long",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Path = append(append([]string{}, proof.Path...), proof.Path[0])
+				return proof
+			},
+		},
+		{
+			name:    "Invalid hash",
+			message: messages[4],
+			proof: func(proof Proof) Proof {
+				proof.Path = []string{"abcd", proof.Path[1], proof.Path[2]}
+				return proof
+			},
+		},
+		{
+			name:    "Other root",
+			message: messages[4],
+			root:    hex.EncodeToString(LeafHash(messages[4])),
+		},
+		{
+			name:    "Invalid root",
+			message: messages[4],
+			root:    "root",
+		},
+		{
+			// A node hash must not be accepted as a message
+			name:    "Node as message",
+			message: append(append([]byte{}, tree.levels[0][4]...), tree.levels[0][5]...),
+			proof: func(proof Proof) Proof {
+				proof.Path = proof.Path[1:]
+				proof.Index = 2
+				proof.Size = 4
+				return proof
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			modified := Proof{Index: proof.Index, Size: proof.Size, Path: append([]string{}, proof.Path...)}
+			if tt.proof != nil {
+				modified = tt.proof(modified)
+			}
+
+			root := tree.Root()
+			if tt.root != "" {
+				root = tt.root
+			}
+
+			s.ErrorIs(VerifyProof(tt.message, modified, root), ErrInvalidProof)
+		})
+	}
+}
+
+func (s *treeTestSuite) TestFromMessages() {
+	// The raw bytes are kept, including the whitespace json.Marshal would drop
+	message, err := ocmf.ParseMessage(`OCMF|{ "FV": "1.0", "PG": "T1", "MS": "meter" }|{"SD":"3045"}`)
+	s.Require().NoError(err)
+
+	tree, err := FromMessages([]ocmf.Message{*message})
+	s.Require().NoError(err)
+
+	proof, err := tree.Proof(0)
+	s.Require().NoError(err)
+	s.NoError(VerifyProof([]byte(message.String()), *proof, tree.Root()))
+	s.Equal(hex.EncodeToString(LeafHash([]byte(`OCMF|{ "FV": "1.0", "PG": "T1", "MS": "meter" }|{"SD":"3045"}`))), tree.Root())
+}
+
+func TestTree(t *testing.T) {
+	suite.Run(t, new(treeTestSuite))
+}
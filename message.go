@@ -0,0 +1,173 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const messageHeader = "OCMF"
+
+// Message is a complete OCMF message as it is produced by the Builder or received from a meter.
+// RawPayload and RawSignature hold the sections exactly as they were signed or transmitted,
+// since the signature must be verified against the original bytes and not a re-encoded payload.
+type Message struct {
+	Payload      PayloadSection
+	RawPayload   []byte
+	Signature    Signature
+	RawSignature []byte
+}
+
+// NewMessage encodes the payload and signature sections into a Message.
+func NewMessage(payload PayloadSection, signature Signature) (*Message, error) {
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	rawSignature, err := json.Marshal(signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signature")
+	}
+
+	return &Message{
+		Payload:      payload,
+		RawPayload:   rawPayload,
+		Signature:    signature,
+		RawSignature: rawSignature,
+	}, nil
+}
+
+// ParseMessage splits the OCMF string into its sections and decodes them. The message is neither validated nor verified.
+// Omitted SA, SE and SM fields are left empty, see Signature.WithDefaults.
+func ParseMessage(data string) (*Message, error) {
+	if !strings.HasPrefix(data, messageHeader+"|") {
+		return nil, ErrInvalidFormat
+	}
+
+	data, _ = strings.CutPrefix(data, messageHeader+"|")
+
+	separator := payloadSeparator(data)
+	if separator < 0 || strings.Contains(data[separator+1:], "|") {
+		return nil, ErrInvalidFormat
+	}
+
+	message := Message{
+		RawPayload:   []byte(data[:separator]),
+		RawSignature: []byte(data[separator+1:]),
+	}
+
+	err := json.Unmarshal(message.RawPayload, &message.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal payload")
+	}
+
+	err = json.Unmarshal(message.RawSignature, &message.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal signature")
+	}
+
+	return &message, nil
+}
+
+// payloadSeparator returns the index of the "|" between the payload and signature sections, or -1.
+// Strings in the payload, e.g. the tariff text, may contain "|" themselves, so the payload ends where its JSON
+// value ends. Payloads that are not valid JSON are split at the first "|" so decoding reports the JSON error.
+func payloadSeparator(data string) int {
+	decoder := json.NewDecoder(strings.NewReader(data))
+
+	var payload json.RawMessage
+	if err := decoder.Decode(&payload); err != nil {
+		return strings.Index(data, "|")
+	}
+
+	end := int(decoder.InputOffset())
+	separator := strings.IndexFunc(data[end:], func(r rune) bool {
+		return !unicode.IsSpace(r)
+	})
+	if separator < 0 || data[end+separator] != '|' {
+		return -1
+	}
+
+	return end + separator
+}
+
+// String returns the message in the OCMF|{payload}|{signature} transport format.
+func (m Message) String() string {
+	return string(m.Bytes())
+}
+
+func (m Message) Bytes() []byte {
+	rawPayload := m.RawPayload
+	if len(rawPayload) == 0 {
+		rawPayload, _ = json.Marshal(m.Payload)
+	}
+
+	rawSignature := m.RawSignature
+	if len(rawSignature) == 0 {
+		rawSignature, _ = json.Marshal(m.Signature)
+	}
+
+	data := make([]byte, 0, len(messageHeader)+len(rawPayload)+len(rawSignature)+2)
+	data = append(data, messageHeader...)
+	data = append(data, '|')
+	data = append(data, rawPayload...)
+	data = append(data, '|')
+	data = append(data, rawSignature...)
+	return data
+}
+
+func (m Message) MarshalText() ([]byte, error) {
+	return m.Bytes(), nil
+}
+
+func (m *Message) UnmarshalText(data []byte) error {
+	message, err := ParseMessage(string(data))
+	if err != nil {
+		return err
+	}
+
+	*m = *message
+	return nil
+}
+
+// Validate validates both the payload and the signature section.
+func (m *Message) Validate() error {
+	if err := m.Payload.Validate(); err != nil {
+		return errors.Wrap(err, "payload validation failed")
+	}
+
+	// Omitted signature fields are valid in a message, the defaults of the specification apply
+	if err := m.Signature.WithDefaults().Validate(); err != nil {
+		return errors.Wrap(err, "signature validation failed")
+	}
+
+	return nil
+}
+
+// Verify checks the signature against the raw payload bytes.
+func (m *Message) Verify(publicKey *ecdsa.PublicKey) (bool, error) {
+	if len(m.RawPayload) == 0 {
+		return m.Signature.Verify(m.Payload, publicKey)
+	}
+
+	return m.Signature.VerifyBytes(m.RawPayload, publicKey)
+}
+
+// VerifyContext checks the signature against the key the resolver returns for the meter serial of the payload.
+func (m *Message) VerifyContext(ctx context.Context, resolver KeyResolver) (bool, error) {
+	if resolver == nil {
+		return false, errors.New("key resolver is required")
+	}
+
+	publicKey, err := SelectPublicKey(ctx, resolver, m.Payload.MeterSerial, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return m.Verify(publicKey)
+}
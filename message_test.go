@@ -0,0 +1,185 @@
+package ocmf_go
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type messageTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+}
+
+func (s *messageTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+}
+
+func (s *messageTestSuite) buildMessage() *Message {
+	message, err := NewBuilder(s.privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 1.0,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+	return message
+}
+
+func (s *messageTestSuite) TestBuild() {
+	message := s.buildMessage()
+
+	s.Equal("T1", message.Payload.Pagination)
+	s.NotEmpty(message.RawPayload)
+	s.NotEmpty(message.Signature.Data)
+	s.NotEmpty(message.RawSignature)
+	s.Equal("OCMF|"+string(message.RawPayload)+"|"+string(message.RawSignature), message.String())
+	s.Equal(message.String(), string(message.Bytes()))
+
+	valid, err := message.Verify(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+}
+
+func (s *messageTestSuite) TestParseMessage() {
+	built := s.buildMessage()
+
+	parsed, err := ParseMessage(built.String())
+	s.Require().NoError(err)
+	s.Equal(*built, *parsed)
+
+	parsed, err = ParseMessage(examplePayload)
+	s.Require().NoError(err)
+	s.Equal("BQ27400330016", parsed.Payload.MeterSerial)
+	// Raw sections are kept exactly as received
+	s.Equal(examplePayload, parsed.String())
+
+	_, err = ParseMessage("OCMF|{}")
+	s.ErrorIs(err, ErrInvalidFormat)
+}
+
+func (s *messageTestSuite) TestParseMessage_separatorInPayload() {
+	message, err := NewBuilder(s.privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		WithTariffText("0,49 €/kWh | 0,10 €/min").
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 1.0,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+
+	parsed, err := ParseMessage(message.String())
+	s.Require().NoError(err)
+	s.Equal(*message, *parsed)
+
+	valid, err := parsed.Verify(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	_, err = ParseMessage(message.String() + "|{}")
+	s.ErrorIs(err, ErrInvalidFormat)
+
+	_, err = ParseMessage(`OCMF|{"PG":"T1"} {"MS":"1"}|{"SD":"00"}`)
+	s.ErrorIs(err, ErrInvalidFormat)
+}
+
+func (s *messageTestSuite) TestVerify_rawPayload() {
+	payload := PayloadSection{
+		FormatVersion: OcmfVersion,
+		Pagination:    "T1",
+		MeterSerial:   "exampleSerial123",
+	}
+
+	// Sign a payload encoded differently from what json.Marshal would produce
+	rawPayload := []byte("{\n \"FV\": \"0.4\",\n \"PG\": \"T1\",\n \"MS\": \"exampleSerial123\"\n}")
+	signature := NewDefaultSignature()
+	s.Require().NoError(signature.SignBytes(rawPayload, s.privateKey))
+
+	message := Message{Payload: payload, RawPayload: rawPayload, Signature: *signature}
+	valid, err := message.Verify(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	// The re-encoded payload differs from the signed bytes
+	valid, err = signature.Verify(payload, &s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.False(valid)
+}
+
+func (s *messageTestSuite) TestParseMessage_signatureDefaults() {
+	built := s.buildMessage()
+
+	// Only the signature data, as sent by many meters
+	data := "OCMF|" + string(built.RawPayload) + `|{"SD":"` + built.Signature.Data + `"}`
+	message, err := ParseMessage(data)
+	s.Require().NoError(err)
+
+	// The signature is kept as received, so it marshals to the same section
+	s.Equal(Signature{Data: built.Signature.Data}, message.Signature)
+	rawSignature, err := json.Marshal(message.Signature)
+	s.Require().NoError(err)
+	s.Equal(`{"SD":"`+built.Signature.Data+`"}`, string(rawSignature))
+	s.Equal(data, message.String())
+
+	// The defaults apply to validation and verification
+	s.Equal(SignatureAlgorithmECDSAsecp256r1SHA256, message.Signature.WithDefaults().Algorithm)
+	s.NoError(message.Validate())
+
+	valid, err := message.Verify(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	_, err = NewParser(WithAutomaticValidation(), WithAutomaticSignatureVerification(&s.privateKey.PublicKey)).
+		ParseOcmfMessageFromString(data).
+		GetMessage()
+	s.NoError(err)
+}
+
+func (s *messageTestSuite) TestMarshalText() {
+	built := s.buildMessage()
+
+	type envelope struct {
+		Message Message `json:"message"`
+	}
+
+	data, err := json.Marshal(envelope{Message: *built})
+	s.Require().NoError(err)
+
+	decoded := envelope{}
+	s.Require().NoError(json.Unmarshal(data, &decoded))
+	s.Equal(*built, decoded.Message)
+
+	s.Error(json.Unmarshal([]byte(`{"message":"OCMF|{}"}`), &decoded))
+}
+
+func (s *messageTestSuite) TestValidate() {
+	message := s.buildMessage()
+	s.NoError(message.Validate())
+
+	message.Payload.MeterSerial = ""
+	s.ErrorContains(message.Validate(), "payload validation failed")
+
+	message = s.buildMessage()
+	message.Signature.Data = ""
+	s.ErrorContains(message.Validate(), "signature validation failed")
+}
+
+func TestMessage(t *testing.T) {
+	suite.Run(t, new(messageTestSuite))
+}
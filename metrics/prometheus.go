@@ -0,0 +1,238 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds in seconds of the verification duration histogram.
+// A P-256 verification takes well below a millisecond, the upper buckets cover slow key resolvers.
+var DefaultBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+type Option func(*Prometheus)
+
+// WithNamespace sets the prefix of the metric names. The default is "ocmf".
+func WithNamespace(namespace string) Option {
+	return func(p *Prometheus) {
+		p.namespace = namespace
+	}
+}
+
+// WithBuckets sets the upper bounds in seconds of the verification duration histogram.
+func WithBuckets(buckets ...float64) Option {
+	return func(p *Prometheus) {
+		p.buckets = slices.Clone(buckets)
+		slices.Sort(p.buckets)
+	}
+}
+
+// Prometheus collects the metrics of parsers and builders and exposes them in the Prometheus text format.
+// It implements ocmf.Metrics and http.Handler, so it can be served on the metrics endpoint of an application
+// without depending on the Prometheus client library.
+type Prometheus struct {
+	mu        sync.Mutex
+	namespace string
+	buckets   []float64
+
+	parsed             uint64
+	invalid            map[ocmf.FailureReason]uint64
+	verificationFailed map[ocmf.FailureReason]uint64
+	built              uint64
+	buildFailed        map[ocmf.FailureReason]uint64
+
+	// bucketCounts holds the number of observations per bucket, the last one counts the observations above all buckets.
+	bucketCounts []uint64
+	durationSum  float64
+	durationN    uint64
+}
+
+var _ ocmf.Metrics = (*Prometheus)(nil)
+
+func NewPrometheus(opts ...Option) *Prometheus {
+	p := &Prometheus{
+		namespace:          "ocmf",
+		buckets:            DefaultBuckets,
+		invalid:            make(map[ocmf.FailureReason]uint64),
+		verificationFailed: make(map[ocmf.FailureReason]uint64),
+		buildFailed:        make(map[ocmf.FailureReason]uint64),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.bucketCounts = make([]uint64, len(p.buckets)+1)
+	return p
+}
+
+func (p *Prometheus) MessageParsed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.parsed++
+}
+
+func (p *Prometheus) MessageInvalid(reason ocmf.FailureReason) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.invalid[reason]++
+}
+
+func (p *Prometheus) VerificationFailed(reason ocmf.FailureReason) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.verificationFailed[reason]++
+}
+
+func (p *Prometheus) VerificationDuration(duration time.Duration) {
+	seconds := duration.Seconds()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index, _ := slices.BinarySearch(p.buckets, seconds)
+	p.bucketCounts[index]++
+	p.durationSum += seconds
+	p.durationN++
+}
+
+func (p *Prometheus) MessageBuilt() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.built++
+}
+
+func (p *Prometheus) BuildFailed(reason ocmf.FailureReason) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buildFailed[reason]++
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := &countingWriter{w: bufio.NewWriter(w)}
+
+	p.writeCounter(out, "messages_parsed_total", "Number of messages passed to a parser.", p.parsed)
+	p.writeCounterVec(out, "messages_invalid_total", "Number of messages that could not be decoded or failed the validation.", p.invalid)
+	p.writeCounterVec(out, "verification_failures_total", "Number of messages whose signature is invalid or could not be verified.", p.verificationFailed)
+	p.writeHistogram(out, "verification_duration_seconds", "Duration of signature verifications, including the key resolution.")
+	p.writeCounter(out, "messages_built_total", "Number of built and signed messages.", p.built)
+	p.writeCounterVec(out, "build_failures_total", "Number of messages that could not be built.", p.buildFailed)
+
+	if out.err == nil {
+		out.err = out.w.Flush()
+	}
+
+	if out.err != nil {
+		return out.n, errors.Wrap(out.err, "failed to write metrics")
+	}
+
+	return out.n, nil
+}
+
+// ServeHTTP serves the metrics, e.g. on /metrics.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = p.WriteTo(w)
+}
+
+func (p *Prometheus) name(name string) string {
+	if p.namespace == "" {
+		return name
+	}
+
+	return p.namespace + "_" + name
+}
+
+func (p *Prometheus) writeHeader(out *countingWriter, name, help, metricType string) {
+	out.write("# HELP ", name, " ", help, "\n")
+	out.write("# TYPE ", name, " ", metricType, "\n")
+}
+
+func (p *Prometheus) writeCounter(out *countingWriter, name, help string, value uint64) {
+	name = p.name(name)
+	p.writeHeader(out, name, help, "counter")
+	out.write(name, " ", strconv.FormatUint(value, 10), "\n")
+}
+
+// writeCounterVec writes a counter with a reason label. The reasons are sorted, so the output is stable.
+func (p *Prometheus) writeCounterVec(out *countingWriter, name, help string, values map[ocmf.FailureReason]uint64) {
+	name = p.name(name)
+	p.writeHeader(out, name, help, "counter")
+
+	reasons := make([]string, 0, len(values))
+	for reason := range values {
+		reasons = append(reasons, string(reason))
+	}
+	sort.Strings(reasons)
+
+	for _, reason := range reasons {
+		value := values[ocmf.FailureReason(reason)]
+		out.write(name, `{reason="`, escapeLabel(reason), `"} `, strconv.FormatUint(value, 10), "\n")
+	}
+}
+
+func (p *Prometheus) writeHistogram(out *countingWriter, name, help string) {
+	name = p.name(name)
+	p.writeHeader(out, name, help, "histogram")
+
+	// Buckets are cumulative
+	var cumulative uint64
+	for i, bucket := range p.buckets {
+		cumulative += p.bucketCounts[i]
+		out.write(name, `_bucket{le="`, formatFloat(bucket), `"} `, strconv.FormatUint(cumulative, 10), "\n")
+	}
+
+	out.write(name, `_bucket{le="+Inf"} `, strconv.FormatUint(p.durationN, 10), "\n")
+	out.write(name, "_sum ", formatFloat(p.durationSum), "\n")
+	out.write(name, "_count ", strconv.FormatUint(p.durationN, 10), "\n")
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// countingWriter keeps the first error and the number of written bytes.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) write(parts ...string) {
+	for _, part := range parts {
+		if c.err != nil {
+			return
+		}
+
+		n, err := c.w.WriteString(part)
+		c.n += int64(n)
+		c.err = err
+	}
+}
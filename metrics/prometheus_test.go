@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type prometheusTestSuite struct {
+	suite.Suite
+}
+
+func (s *prometheusTestSuite) TestWriteTo() {
+	metrics := NewPrometheus(WithBuckets(0.01, 0.001, 0.1))
+
+	metrics.MessageParsed()
+	metrics.MessageParsed()
+	metrics.MessageInvalid(ocmf.ReasonValidation)
+	metrics.MessageInvalid(ocmf.ReasonFormat)
+	metrics.MessageInvalid(ocmf.ReasonFormat)
+	metrics.VerificationFailed(ocmf.ReasonSignatureMismatch)
+	metrics.VerificationDuration(500 * time.Microsecond)
+	metrics.VerificationDuration(10 * time.Millisecond)
+	metrics.VerificationDuration(2 * time.Second)
+	metrics.MessageBuilt()
+
+	expected := `# HELP ocmf_messages_parsed_total Number of messages passed to a parser.
+# TYPE ocmf_messages_parsed_total counter
+ocmf_messages_parsed_total 2
+# HELP ocmf_messages_invalid_total Number of messages that could not be decoded or failed the validation.
+# TYPE ocmf_messages_invalid_total counter
+ocmf_messages_invalid_total{reason="format"} 2
+ocmf_messages_invalid_total{reason="validation"} 1
+# HELP ocmf_verification_failures_total Number of messages whose signature is invalid or could not be verified.
+# TYPE ocmf_verification_failures_total counter
+ocmf_verification_failures_total{reason="signature_mismatch"} 1
+# HELP ocmf_verification_duration_seconds Duration of signature verifications, including the key resolution.
+# TYPE ocmf_verification_duration_seconds histogram
+ocmf_verification_duration_seconds_bucket{le="0.001"} 1
+ocmf_verification_duration_seconds_bucket{le="0.01"} 2
+ocmf_verification_duration_seconds_bucket{le="0.1"} 2
+ocmf_verification_duration_seconds_bucket{le="+Inf"} 3
+ocmf_verification_duration_seconds_sum 2.0105
+ocmf_verification_duration_seconds_count 3
+# HELP ocmf_messages_built_total Number of built and signed messages.
+# TYPE ocmf_messages_built_total counter
+ocmf_messages_built_total 1
+# HELP ocmf_build_failures_total Number of messages that could not be built.
+# TYPE ocmf_build_failures_total counter
+`
+
+	out := &bytes.Buffer{}
+	n, err := metrics.WriteTo(out)
+	s.Require().NoError(err)
+	s.Equal(expected, out.String())
+	s.EqualValues(len(expected), n)
+}
+
+func (s *prometheusTestSuite) TestNamespace() {
+	metrics := NewPrometheus(WithNamespace("charging"))
+	metrics.BuildFailed(`sign"ing\`)
+
+	out := &bytes.Buffer{}
+	_, err := metrics.WriteTo(out)
+	s.Require().NoError(err)
+	s.Contains(out.String(), "charging_messages_parsed_total 0\n")
+	s.Contains(out.String(), `charging_build_failures_total{reason="sign\"ing\\"} 1`)
+	s.Contains(out.String(), `charging_verification_duration_seconds_bucket{le="0.0001"} 0`)
+
+	metrics = NewPrometheus(WithNamespace(""))
+	out.Reset()
+	_, err = metrics.WriteTo(out)
+	s.Require().NoError(err)
+	s.Contains(out.String(), "\nmessages_parsed_total 0\n")
+}
+
+func (s *prometheusTestSuite) TestServeHTTP() {
+	metrics := NewPrometheus()
+	metrics.MessageBuilt()
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(ContentType, recorder.Header().Get("Content-Type"))
+	s.Contains(recorder.Body.String(), "ocmf_messages_built_total 1\n")
+}
+
+func (s *prometheusTestSuite) TestParserAndBuilder() {
+	metrics := NewPrometheus()
+
+	privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+
+	template := ocmf.NewBuilder(privateKey, ocmf.WithBuilderMetrics(metrics)).
+		WithMeterSerial("meter1").
+		Template()
+
+	parser := ocmf.NewParser(ocmf.WithMetrics(metrics), ocmf.WithAutomaticSignatureVerification(&privateKey.PublicKey))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			message, err := template.NewBuilder().
+				WithPagination("T1").
+				WithIdentificationType(string(ocmf.RfidNone)).
+				AddReading(ocmf.Reading{
+					Time:         "2018-07-24T13:22:04,000+0200 S",
+					ReadingValue: 10,
+					ReadingUnit:  string(ocmf.UnitskWh),
+					Status:       string(ocmf.MeterOk),
+				}).
+				Build()
+			if !s.NoError(err) {
+				return
+			}
+
+			_, _ = parser.ParseMessage(*message).GetMessage()
+			_, _ = parser.ParseOcmfMessageFromString(strings.Replace(message.String(), `"RV":10`, `"RV":11`, 1)).GetMessage()
+		}()
+	}
+	wg.Wait()
+
+	out := &bytes.Buffer{}
+	_, err = metrics.WriteTo(out)
+	s.Require().NoError(err)
+	s.Contains(out.String(), "ocmf_messages_built_total 10\n")
+	s.Contains(out.String(), "ocmf_messages_parsed_total 20\n")
+	s.Contains(out.String(), `ocmf_verification_failures_total{reason="signature_mismatch"} 10`)
+	s.Contains(out.String(), "ocmf_verification_duration_seconds_count 20\n")
+}
+
+func TestPrometheus(t *testing.T) {
+	suite.Run(t, new(prometheusTestSuite))
+}
@@ -0,0 +1,192 @@
+package ocpi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+// EncodingMethodOCMF is the encoding_method of OCMF signed data.
+const EncodingMethodOCMF = "OCMF"
+
+type Nature string
+
+const (
+	NatureStart        = Nature("Start")
+	NatureEnd          = Nature("End")
+	NatureIntermediate = Nature("Intermediate")
+)
+
+var (
+	ErrNoSignedValues    = errors.New("no signed values")
+	ErrPlainDataMismatch = errors.New("plain data does not match the signed data")
+)
+
+// SignedValue is the OCPI 2.2 SignedValue class. PlainData holds the OCMF payload section
+// and SignedData the complete, base64 encoded OCMF message.
+type SignedValue struct {
+	Nature     Nature `json:"nature"`
+	PlainData  string `json:"plain_data"`
+	SignedData string `json:"signed_data"`
+}
+
+// SignedData is the OCPI 2.2 SignedData class, used in the signed_data field of a CDR.
+type SignedData struct {
+	EncodingMethod        string        `json:"encoding_method"`
+	EncodingMethodVersion *int          `json:"encoding_method_version,omitempty"`
+	PublicKey             string        `json:"public_key,omitempty"`
+	SignedValues          []SignedValue `json:"signed_values"`
+	URL                   string        `json:"url,omitempty"`
+}
+
+// CDR contains the fields of an OCPI CDR needed to verify its signed data.
+type CDR struct {
+	ID         string      `json:"id"`
+	SignedData *SignedData `json:"signed_data,omitempty"`
+}
+
+// VerifiedValue is a signed value whose OCMF message was successfully verified.
+type VerifiedValue struct {
+	Nature  Nature
+	Message ocmf.Message
+}
+
+// NatureOf derives the nature of the message from the transaction types of its readings.
+func NatureOf(message ocmf.Message) Nature {
+	begin := len(message.Payload.Readings) > 0
+	for _, reading := range message.Payload.Readings {
+		transactionType := ocmf.TransactionType(reading.Transaction)
+		if transactionType.IsEnd() {
+			return NatureEnd
+		}
+
+		if transactionType != ocmf.TransactionBegin {
+			begin = false
+		}
+	}
+
+	if begin {
+		return NatureStart
+	}
+
+	return NatureIntermediate
+}
+
+// NewSignedData converts the messages of a session into an OCPI SignedData object.
+// Every message is validated and verified with the public key before it is exported.
+func NewSignedData(messages []ocmf.Message, publicKey *ecdsa.PublicKey, url string) (*SignedData, error) {
+	if len(messages) == 0 {
+		return nil, ErrNoSignedValues
+	}
+
+	der, err := ocmf.MarshalPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := ocmf.NewParser(ocmf.WithAutomaticValidation(), ocmf.WithAutomaticSignatureVerification(publicKey))
+
+	signedData := &SignedData{
+		EncodingMethod: EncodingMethodOCMF,
+		PublicKey:      base64.StdEncoding.EncodeToString(der),
+		SignedValues:   []SignedValue{},
+		URL:            url,
+	}
+
+	for i, message := range messages {
+		verified, err := parser.ParseMessage(message).GetMessage()
+		if err != nil {
+			return nil, errors.Wrapf(err, "message %d", i)
+		}
+
+		signedData.SignedValues = append(signedData.SignedValues, SignedValue{
+			Nature:     NatureOf(*verified),
+			PlainData:  string(verified.RawPayload),
+			SignedData: base64.StdEncoding.EncodeToString(verified.Bytes()),
+		})
+	}
+
+	return signedData, nil
+}
+
+// VerifySignedData parses every signed value and verifies it. The key is selected with ocmf.SelectPublicKey,
+// so the public_key of the object is only trusted if no resolver is given.
+func VerifySignedData(ctx context.Context, signedData SignedData, resolver ocmf.KeyResolver) ([]VerifiedValue, error) {
+	if signedData.EncodingMethod != EncodingMethodOCMF {
+		return nil, errors.Wrapf(ocmf.ErrUnsupportedEncodingMethod, "%q", signedData.EncodingMethod)
+	}
+
+	if len(signedData.SignedValues) == 0 {
+		return nil, ErrNoSignedValues
+	}
+
+	var embeddedKey *ecdsa.PublicKey
+	if signedData.PublicKey != "" {
+		publicKey, err := ocmf.ParsePublicKey([]byte(signedData.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+
+		embeddedKey = publicKey
+	}
+
+	verifiedValues := []VerifiedValue{}
+	for i, signedValue := range signedData.SignedValues {
+		message, err := verifySignedValue(ctx, signedValue, resolver, embeddedKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "signed value %d", i)
+		}
+
+		verifiedValues = append(verifiedValues, VerifiedValue{
+			Nature:  signedValue.Nature,
+			Message: *message,
+		})
+	}
+
+	return verifiedValues, nil
+}
+
+// VerifyCDR verifies the signed data of a JSON encoded OCPI CDR.
+func VerifyCDR(ctx context.Context, data []byte, resolver ocmf.KeyResolver) ([]VerifiedValue, error) {
+	cdr := CDR{}
+	err := json.Unmarshal(data, &cdr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal CDR")
+	}
+
+	if cdr.SignedData == nil {
+		return nil, errors.Wrapf(ErrNoSignedValues, "CDR %s", cdr.ID)
+	}
+
+	return VerifySignedData(ctx, *cdr.SignedData, resolver)
+}
+
+func verifySignedValue(ctx context.Context, signedValue SignedValue, resolver ocmf.KeyResolver, embeddedKey *ecdsa.PublicKey) (*ocmf.Message, error) {
+	data, err := base64.StdEncoding.DecodeString(signedValue.SignedData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode signed data")
+	}
+
+	message, err := ocmf.ParseMessage(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if signedValue.PlainData != "" && signedValue.PlainData != string(message.RawPayload) {
+		return nil, ErrPlainDataMismatch
+	}
+
+	publicKey, err := ocmf.SelectPublicKey(ctx, resolver, message.Payload.MeterSerial, embeddedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return ocmf.NewParser(
+		ocmf.WithAutomaticValidation(),
+		ocmf.WithAutomaticSignatureVerification(publicKey),
+	).ParseMessage(*message).GetMessage()
+}
@@ -0,0 +1,221 @@
+package ocpi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type signedDataTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	messages   []ocmf.Message
+}
+
+func (s *signedDataTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	template := ocmf.NewBuilder(privateKey).WithMeterSerial("exampleSerial123").Template()
+	s.messages = []ocmf.Message{}
+
+	for _, reading := range []ocmf.Reading{
+		{Time: "2018-07-24T13:22:04,000+0200 S", Transaction: "B", ReadingValue: 10},
+		{Time: "2018-07-24T13:26:04,000+0200 S", Transaction: "E", ReadingValue: 12},
+	} {
+		reading.ReadingUnit = string(ocmf.UnitskWh)
+		reading.Status = string(ocmf.MeterOk)
+
+		message, err := template.NewBuilder().
+			WithPagination(fmt.Sprintf("T%d", len(s.messages)+1)).
+			WithIdentificationType(string(ocmf.RfidNone)).
+			AddReading(reading).
+			Build()
+		s.Require().NoError(err)
+
+		s.messages = append(s.messages, *message)
+	}
+}
+
+func (s *signedDataTestSuite) TestNatureOf() {
+	tests := []struct {
+		name         string
+		transactions []string
+		expected     Nature
+	}{
+		{name: "Begin", transactions: []string{"B"}, expected: NatureStart},
+		{name: "End", transactions: []string{"E"}, expected: NatureEnd},
+		{name: "Aborted", transactions: []string{"A"}, expected: NatureEnd},
+		{name: "Begin and end", transactions: []string{"B", "E"}, expected: NatureEnd},
+		{name: "Charging", transactions: []string{"C"}, expected: NatureIntermediate},
+		{name: "No transaction", transactions: []string{""}, expected: NatureIntermediate},
+		{name: "No readings", transactions: []string{}, expected: NatureIntermediate},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			message := ocmf.Message{}
+			for _, transaction := range tt.transactions {
+				message.Payload.Readings = append(message.Payload.Readings, ocmf.Reading{Transaction: transaction})
+			}
+
+			s.Equal(tt.expected, NatureOf(message))
+		})
+	}
+}
+
+func (s *signedDataTestSuite) TestNewSignedData() {
+	signedData, err := NewSignedData(s.messages, &s.privateKey.PublicKey, "https://example.com/transparency")
+	s.Require().NoError(err)
+
+	s.Equal(EncodingMethodOCMF, signedData.EncodingMethod)
+	s.Equal("https://example.com/transparency", signedData.URL)
+	s.NotEmpty(signedData.PublicKey)
+	s.Require().Len(signedData.SignedValues, 2)
+	s.Equal(NatureStart, signedData.SignedValues[0].Nature)
+	s.Equal(NatureEnd, signedData.SignedValues[1].Nature)
+	s.Equal(string(s.messages[0].RawPayload), signedData.SignedValues[0].PlainData)
+	s.Equal(base64.StdEncoding.EncodeToString(s.messages[1].Bytes()), signedData.SignedValues[1].SignedData)
+
+	data, err := json.Marshal(signedData)
+	s.Require().NoError(err)
+	s.Contains(string(data), `"encoding_method":"OCMF"`)
+	s.Contains(string(data), `"signed_values":[{"nature":"Start"`)
+}
+
+func (s *signedDataTestSuite) TestNewSignedData_invalid() {
+	_, err := NewSignedData(nil, &s.privateKey.PublicKey, "")
+	s.ErrorIs(err, ErrNoSignedValues)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	_, err = NewSignedData(s.messages, &otherKey.PublicKey, "")
+	s.ErrorIs(err, ocmf.ErrVerificationFailure)
+	s.ErrorContains(err, "message 0")
+}
+
+func (s *signedDataTestSuite) TestVerifySignedData() {
+	signedData, err := NewSignedData(s.messages, &s.privateKey.PublicKey, "")
+	s.Require().NoError(err)
+
+	values, err := VerifySignedData(context.Background(), *signedData, nil)
+	s.Require().NoError(err)
+	s.Require().Len(values, 2)
+	s.Equal(NatureStart, values[0].Nature)
+	s.Equal(s.messages[0], values[0].Message)
+	s.Equal(s.messages[1], values[1].Message)
+
+	registry := ocmf.NewKeyRegistry()
+	registry.Register("exampleSerial123", &s.privateKey.PublicKey)
+
+	withoutKey := *signedData
+	withoutKey.PublicKey = ""
+	values, err = VerifySignedData(context.Background(), withoutKey, registry)
+	s.Require().NoError(err)
+	s.Len(values, 2)
+}
+
+func (s *signedDataTestSuite) TestVerifySignedData_invalid() {
+	signedData, err := NewSignedData(s.messages, &s.privateKey.PublicKey, "")
+	s.Require().NoError(err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	otherRegistry := ocmf.NewKeyRegistry()
+	otherRegistry.Register("exampleSerial123", &otherKey.PublicKey)
+
+	tamperedMessage := s.messages[1]
+	tamperedMessage.RawPayload = []byte(string(tamperedMessage.RawPayload[:len(tamperedMessage.RawPayload)-1]) + ` }`)
+
+	tests := []struct {
+		name     string
+		modify   func(data *SignedData)
+		resolver ocmf.KeyResolver
+		error    error
+	}{
+		{
+			name:   "Unsupported encoding method",
+			modify: func(data *SignedData) { data.EncodingMethod = "EDL40" },
+			error:  ocmf.ErrUnsupportedEncodingMethod,
+		},
+		{
+			name:   "No signed values",
+			modify: func(data *SignedData) { data.SignedValues = nil },
+			error:  ErrNoSignedValues,
+		},
+		{
+			name:   "Invalid public key",
+			modify: func(data *SignedData) { data.PublicKey = "key" },
+			error:  ocmf.ErrInvalidPublicKey,
+		},
+		{
+			name:     "Public key does not match the registry",
+			modify:   func(data *SignedData) {},
+			resolver: otherRegistry,
+			error:    ocmf.ErrPublicKeyMismatch,
+		},
+		{
+			name: "Plain data mismatch",
+			modify: func(data *SignedData) {
+				data.SignedValues[0].PlainData = "{}"
+			},
+			error: ErrPlainDataMismatch,
+		},
+		{
+			name: "Tampered payload",
+			modify: func(data *SignedData) {
+				data.SignedValues[1].PlainData = ""
+				data.SignedValues[1].SignedData = base64.StdEncoding.EncodeToString(tamperedMessage.Bytes())
+			},
+			error: ocmf.ErrVerificationFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			modified := *signedData
+			modified.SignedValues = append([]SignedValue{}, signedData.SignedValues...)
+			tt.modify(&modified)
+
+			_, err := VerifySignedData(context.Background(), modified, tt.resolver)
+			s.ErrorIs(err, tt.error)
+		})
+	}
+}
+
+func (s *signedDataTestSuite) TestVerifyCDR() {
+	signedData, err := NewSignedData(s.messages, &s.privateKey.PublicKey, "")
+	s.Require().NoError(err)
+
+	data, err := json.Marshal(map[string]any{
+		"country_code": "DE",
+		"party_id":     "ABC",
+		"id":           "12345",
+		"signed_data":  signedData,
+	})
+	s.Require().NoError(err)
+
+	values, err := VerifyCDR(context.Background(), data, nil)
+	s.Require().NoError(err)
+	s.Len(values, 2)
+
+	_, err = VerifyCDR(context.Background(), []byte(`{"id":"12345"}`), nil)
+	s.ErrorIs(err, ErrNoSignedValues)
+
+	_, err = VerifyCDR(context.Background(), []byte(`{`), nil)
+	s.ErrorContains(err, "failed to unmarshal CDR")
+}
+
+func TestSignedData(t *testing.T) {
+	suite.Run(t, new(signedDataTestSuite))
+}
@@ -0,0 +1,67 @@
+package ocpp16
+
+import (
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/pkg/errors"
+)
+
+// SignedMeterValue is an OCMF message received over OCPP 1.6 together with the context it was sent in.
+type SignedMeterValue struct {
+	ConnectorID   int
+	TransactionID *int
+	Context       types.ReadingContext
+	Timestamp     time.Time
+	Message       ocmf.Message
+}
+
+// ExtractFromMeterValues parses every SignedData sampled value of the request with the parser.
+// The parser options decide whether the messages are validated and verified.
+func ExtractFromMeterValues(parser *ocmf.Parser, request core.MeterValuesRequest) ([]SignedMeterValue, error) {
+	return extract(parser, request.ConnectorId, request.TransactionId, request.MeterValue)
+}
+
+// ExtractFromStopTransaction parses every SignedData sampled value of the transaction data.
+// StopTransaction does not carry the connector, so it has to be provided by the caller.
+func ExtractFromStopTransaction(parser *ocmf.Parser, connectorID int, request core.StopTransactionRequest) ([]SignedMeterValue, error) {
+	transactionID := request.TransactionId
+	return extract(parser, connectorID, &transactionID, request.TransactionData)
+}
+
+func extract(parser *ocmf.Parser, connectorID int, transactionID *int, meterValues []types.MeterValue) ([]SignedMeterValue, error) {
+	if parser == nil {
+		parser = ocmf.NewParser()
+	}
+
+	signedMeterValues := []SignedMeterValue{}
+	for i, meterValue := range meterValues {
+		for j, sampledValue := range meterValue.SampledValue {
+			if sampledValue.Format != types.ValueFormatSignedData {
+				continue
+			}
+
+			message, err := parser.ParseOcmfMessageFromString(sampledValue.Value).GetMessage()
+			if err != nil {
+				return nil, errors.Wrapf(err, "meter value %d, sampled value %d", i, j)
+			}
+
+			signedMeterValue := SignedMeterValue{
+				ConnectorID:   connectorID,
+				TransactionID: transactionID,
+				Context:       sampledValue.Context,
+				Message:       *message,
+			}
+
+			if meterValue.Timestamp != nil {
+				signedMeterValue.Timestamp = meterValue.Timestamp.Time
+			}
+
+			signedMeterValues = append(signedMeterValues, signedMeterValue)
+		}
+	}
+
+	return signedMeterValues, nil
+}
@@ -0,0 +1,98 @@
+package ocpp16
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/core"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type extractTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	begin      *ocmf.Message
+	end        *ocmf.Message
+}
+
+func (s *extractTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.begin, err = buildMessage(privateKey, "T1", "B", "2018-07-24T13:22:04,000+0200 S", 10)
+	s.Require().NoError(err)
+
+	s.end, err = buildMessage(privateKey, "T2", "E", "2018-07-24T13:26:04,000+0200 S", 12)
+	s.Require().NoError(err)
+}
+
+func (s *extractTestSuite) meterValues() []types.MeterValue {
+	begin, err := NewTransactionBeginMeterValue(*s.begin)
+	s.Require().NoError(err)
+
+	end, err := NewTransactionEndMeterValue(*s.end)
+	s.Require().NoError(err)
+
+	// Plain values are skipped
+	end.SampledValue = append(end.SampledValue, types.SampledValue{Value: "12000", Format: types.ValueFormatRaw})
+
+	return []types.MeterValue{*begin, *end}
+}
+
+func (s *extractTestSuite) TestExtractFromStopTransaction() {
+	request := core.NewStopTransactionRequest(12000, types.NewDateTime(time.Now()), 42)
+	request.TransactionData = s.meterValues()
+
+	parser := ocmf.NewParser(ocmf.WithAutomaticValidation(), ocmf.WithAutomaticSignatureVerification(&s.privateKey.PublicKey))
+	values, err := ExtractFromStopTransaction(parser, 2, *request)
+	s.Require().NoError(err)
+	s.Require().Len(values, 2)
+
+	s.Equal(2, values[0].ConnectorID)
+	s.Require().NotNil(values[0].TransactionID)
+	s.Equal(42, *values[0].TransactionID)
+	s.Equal(types.ReadingContextTransactionBegin, values[0].Context)
+	s.Equal(*s.begin, values[0].Message)
+	s.True(request.TransactionData[0].Timestamp.Equal(values[0].Timestamp))
+
+	s.Equal(types.ReadingContextTransactionEnd, values[1].Context)
+	s.Equal(*s.end, values[1].Message)
+}
+
+func (s *extractTestSuite) TestExtractFromMeterValues() {
+	request := core.NewMeterValuesRequest(1, s.meterValues())
+	transactionID := 7
+	request.TransactionId = &transactionID
+
+	values, err := ExtractFromMeterValues(nil, *request)
+	s.Require().NoError(err)
+	s.Require().Len(values, 2)
+	s.Equal(1, values[1].ConnectorID)
+	s.Equal(&transactionID, values[1].TransactionID)
+	s.Equal("T2", values[1].Message.Payload.Pagination)
+}
+
+func (s *extractTestSuite) TestExtract_invalid() {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	request := core.NewMeterValuesRequest(1, s.meterValues())
+	parser := ocmf.NewParser(ocmf.WithAutomaticSignatureVerification(&otherKey.PublicKey))
+	_, err = ExtractFromMeterValues(parser, *request)
+	s.ErrorIs(err, ocmf.ErrVerificationFailure)
+	s.ErrorContains(err, "meter value 0, sampled value 0")
+
+	request.MeterValue[1].SampledValue[0].Value = "OCMF|{}"
+	_, err = ExtractFromMeterValues(nil, *request)
+	s.ErrorIs(err, ocmf.ErrInvalidFormat)
+}
+
+func TestExtract(t *testing.T) {
+	suite.Run(t, new(extractTestSuite))
+}
@@ -0,0 +1,118 @@
+package ocpp16
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/pkg/errors"
+)
+
+var ErrNoReadings = errors.New("message has no readings")
+
+// NewSampledValue wraps the OCMF message into a SampledValue with the SignedData format.
+// The measurand is derived from the last reading of the message, see Measurand.
+func NewSampledValue(message ocmf.Message, context types.ReadingContext) types.SampledValue {
+	var measurand types.Measurand
+	if len(message.Payload.Readings) > 0 {
+		measurand = Measurand(message.Payload.Readings[len(message.Payload.Readings)-1])
+	}
+
+	return types.SampledValue{
+		Value:     message.String(),
+		Context:   context,
+		Format:    types.ValueFormatSignedData,
+		Measurand: measurand,
+	}
+}
+
+// Measurand returns the measurand of the register the reading was taken from. The OBIS code of the reading identifier
+// (RI) is used if present, e.g. "1-b:2.8.0" is the exported active energy. Otherwise, energy readings are assumed to
+// be taken from the active energy import register. Other readings return an empty measurand.
+func Measurand(reading ocmf.Reading) types.Measurand {
+	if measurand, found := obisMeasurand(reading.ReadingIdentifier); found {
+		return measurand
+	}
+
+	switch ocmf.Units(reading.ReadingUnit) {
+	case ocmf.UnitsWh, ocmf.UnitskWh:
+		return types.MeasurandEnergyActiveImportRegister
+	default:
+		return ""
+	}
+}
+
+// obisMeasurand maps the OBIS code of cumulative energy registers (C.8.E, e.g. "1-0:1.8.0*255") to a measurand.
+func obisMeasurand(identifier string) (types.Measurand, bool) {
+	_, code, found := strings.Cut(identifier, ":")
+	if !found {
+		return "", false
+	}
+
+	code, _, _ = strings.Cut(code, "*")
+	groups := strings.Split(code, ".")
+	if len(groups) < 2 {
+		return "", false
+	}
+
+	quantity, err := strconv.Atoi(groups[0])
+	if err != nil {
+		return "", false
+	}
+
+	processing, err := strconv.Atoi(groups[1])
+	if err != nil || processing != 8 {
+		return "", false
+	}
+
+	switch quantity {
+	case 1:
+		return types.MeasurandEnergyActiveImportRegister, true
+	case 2:
+		return types.MeasurandEnergyActiveExportRegister, true
+	case 3:
+		return types.MeasurandEnergyReactiveImportRegister, true
+	case 4:
+		return types.MeasurandEnergyReactiveExportRegister, true
+	default:
+		return "", false
+	}
+}
+
+// NewMeterValue creates a MeterValue carrying the OCMF message. The timestamp is taken from the last reading of the message.
+func NewMeterValue(message ocmf.Message, context types.ReadingContext) (*types.MeterValue, error) {
+	timestamp, err := lastReadingTime(message.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MeterValue{
+		Timestamp:    types.NewDateTime(timestamp),
+		SampledValue: []types.SampledValue{NewSampledValue(message, context)},
+	}, nil
+}
+
+// NewTransactionBeginMeterValue creates a MeterValue for the message signed at the start of the transaction.
+func NewTransactionBeginMeterValue(message ocmf.Message) (*types.MeterValue, error) {
+	return NewMeterValue(message, types.ReadingContextTransactionBegin)
+}
+
+// NewTransactionEndMeterValue creates a MeterValue for the message signed at the end of the transaction.
+func NewTransactionEndMeterValue(message ocmf.Message) (*types.MeterValue, error) {
+	return NewMeterValue(message, types.ReadingContextTransactionEnd)
+}
+
+func lastReadingTime(payload ocmf.PayloadSection) (time.Time, error) {
+	if len(payload.Readings) == 0 {
+		return time.Time{}, ErrNoReadings
+	}
+
+	timestamp, _, err := payload.Readings[len(payload.Readings)-1].ParseTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return timestamp, nil
+}
@@ -0,0 +1,124 @@
+package ocpp16
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/lorenzodonini/ocpp-go/ocpp1.6/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type meterValuesTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+}
+
+func (s *meterValuesTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+}
+
+func buildMessage(privateKey *ecdsa.PrivateKey, pagination, transaction, readingTime string, value float64) (*ocmf.Message, error) {
+	return ocmf.NewBuilder(privateKey).
+		WithPagination(pagination).
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(ocmf.RfidNone)).
+		AddReading(ocmf.Reading{
+			Time:         readingTime,
+			Transaction:  transaction,
+			ReadingValue: value,
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		}).
+		Build()
+}
+
+func (s *meterValuesTestSuite) TestNewMeterValue() {
+	message, err := buildMessage(s.privateKey, "T1", "B", "2018-07-24T13:22:04,000+0200 S", 10)
+	s.Require().NoError(err)
+
+	meterValue, err := NewTransactionBeginMeterValue(*message)
+	s.Require().NoError(err)
+	s.True(time.Date(2018, 7, 24, 11, 22, 4, 0, time.UTC).Equal(meterValue.Timestamp.Time))
+	s.Require().Len(meterValue.SampledValue, 1)
+	s.Equal(message.String(), meterValue.SampledValue[0].Value)
+	s.Equal(types.ValueFormatSignedData, meterValue.SampledValue[0].Format)
+	s.Equal(types.ReadingContextTransactionBegin, meterValue.SampledValue[0].Context)
+	s.Equal(types.MeasurandEnergyActiveImportRegister, meterValue.SampledValue[0].Measurand)
+	s.NoError(types.Validate.Struct(meterValue))
+
+	meterValue, err = NewTransactionEndMeterValue(*message)
+	s.Require().NoError(err)
+	s.Equal(types.ReadingContextTransactionEnd, meterValue.SampledValue[0].Context)
+}
+
+func (s *meterValuesTestSuite) TestNewMeterValue_invalidReading() {
+	message := ocmf.Message{Payload: ocmf.PayloadSection{}}
+	_, err := NewMeterValue(message, types.ReadingContextTransactionBegin)
+	s.ErrorIs(err, ErrNoReadings)
+
+	message.Payload.Readings = []ocmf.Reading{{Time: "invalid"}}
+	_, err = NewMeterValue(message, types.ReadingContextTransactionBegin)
+	s.Error(err)
+}
+
+func (s *meterValuesTestSuite) TestMeasurand() {
+	tests := []struct {
+		name     string
+		reading  ocmf.Reading
+		expected types.Measurand
+	}{
+		{
+			name:     "Energy without identifier",
+			reading:  ocmf.Reading{ReadingUnit: string(ocmf.UnitskWh)},
+			expected: types.MeasurandEnergyActiveImportRegister,
+		},
+		{
+			name:     "Active import",
+			reading:  ocmf.Reading{ReadingIdentifier: "1-b:1.8.0", ReadingUnit: string(ocmf.UnitsWh)},
+			expected: types.MeasurandEnergyActiveImportRegister,
+		},
+		{
+			name:     "Active export",
+			reading:  ocmf.Reading{ReadingIdentifier: "1-0:2.8.0*255", ReadingUnit: string(ocmf.UnitskWh)},
+			expected: types.MeasurandEnergyActiveExportRegister,
+		},
+		{
+			name:     "Reactive import",
+			reading:  ocmf.Reading{ReadingIdentifier: "01-00:03.08.00", ReadingUnit: string(ocmf.UnitskWh)},
+			expected: types.MeasurandEnergyReactiveImportRegister,
+		},
+		{
+			name:     "Reactive export",
+			reading:  ocmf.Reading{ReadingIdentifier: "1-0:4.8.0", ReadingUnit: string(ocmf.UnitskWh)},
+			expected: types.MeasurandEnergyReactiveExportRegister,
+		},
+		{
+			name:     "Unknown OBIS code",
+			reading:  ocmf.Reading{ReadingIdentifier: "1-0:16.7.0", ReadingUnit: string(ocmf.UnitskWh)},
+			expected: types.MeasurandEnergyActiveImportRegister,
+		},
+		{
+			name:    "Cable loss",
+			reading: ocmf.Reading{ReadingUnit: string(ocmf.UnitsMilliOhm)},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.Equal(tt.expected, Measurand(tt.reading))
+		})
+	}
+
+	sampledValue := NewSampledValue(ocmf.Message{}, types.ReadingContextSamplePeriodic)
+	s.Empty(sampledValue.Measurand)
+}
+
+func TestMeterValues(t *testing.T) {
+	suite.Run(t, new(meterValuesTestSuite))
+}
@@ -0,0 +1,83 @@
+package ocpp201
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/pkg/errors"
+)
+
+// EncodingMethodOCMF is the encodingMethod of a SignedMeterValue carrying an OCMF message.
+const EncodingMethodOCMF = "OCMF"
+
+// NewSignedMeterValue wraps the OCMF message into a SignedMeterValue. The public key is optional,
+// as charging stations only send it if PublicKeyWithSignedMeterValue is enabled.
+func NewSignedMeterValue(message ocmf.Message, publicKey *ecdsa.PublicKey) (*types.SignedMeterValue, error) {
+	signedMeterValue := &types.SignedMeterValue{
+		SignedMeterData: base64.StdEncoding.EncodeToString(message.Bytes()),
+		SigningMethod:   string(message.Signature.WithDefaults().Algorithm),
+		EncodingMethod:  EncodingMethodOCMF,
+	}
+
+	if publicKey != nil {
+		der, err := ocmf.MarshalPublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		signedMeterValue.PublicKey = base64.StdEncoding.EncodeToString(der)
+	}
+
+	return signedMeterValue, nil
+}
+
+// DecodeSignedMeterValue decodes the OCMF message and the public key, if present. The message is neither validated nor verified.
+func DecodeSignedMeterValue(signedMeterValue types.SignedMeterValue) (*ocmf.Message, *ecdsa.PublicKey, error) {
+	if signedMeterValue.EncodingMethod != EncodingMethodOCMF {
+		return nil, nil, errors.Wrapf(ocmf.ErrUnsupportedEncodingMethod, "%q", signedMeterValue.EncodingMethod)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(signedMeterValue.SignedMeterData)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode signed meter data")
+	}
+
+	message, err := ocmf.ParseMessage(string(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if signedMeterValue.PublicKey == "" {
+		return message, nil, nil
+	}
+
+	publicKey, err := ocmf.ParsePublicKey([]byte(signedMeterValue.PublicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return message, publicKey, nil
+}
+
+// VerifySignedMeterValue decodes, validates and verifies the SignedMeterValue.
+// If a resolver is given, the key registered for the meter is used and an embedded key must match it.
+// Without a resolver, the embedded public key is used.
+func VerifySignedMeterValue(ctx context.Context, signedMeterValue types.SignedMeterValue, resolver ocmf.KeyResolver) (*ocmf.Message, error) {
+	message, embeddedKey, err := DecodeSignedMeterValue(signedMeterValue)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := ocmf.SelectPublicKey(ctx, resolver, message.Payload.MeterSerial, embeddedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return ocmf.NewParser(
+		ocmf.WithAutomaticValidation(),
+		ocmf.WithAutomaticSignatureVerification(publicKey),
+	).ParseMessage(*message).GetMessage()
+}
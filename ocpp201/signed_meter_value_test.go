@@ -0,0 +1,161 @@
+package ocpp201
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/lorenzodonini/ocpp-go/ocpp2.0.1/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type signedMeterValueTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	message    *ocmf.Message
+}
+
+func (s *signedMeterValueTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.message, err = ocmf.NewBuilder(privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(ocmf.RfidNone)).
+		AddReading(ocmf.Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			Transaction:  "B",
+			ReadingValue: 10,
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+}
+
+func (s *signedMeterValueTestSuite) TestNewSignedMeterValue() {
+	signedMeterValue, err := NewSignedMeterValue(*s.message, &s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.Equal(EncodingMethodOCMF, signedMeterValue.EncodingMethod)
+	s.Equal(string(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256), signedMeterValue.SigningMethod)
+	s.Equal(base64.StdEncoding.EncodeToString(s.message.Bytes()), signedMeterValue.SignedMeterData)
+	s.NotEmpty(signedMeterValue.PublicKey)
+	s.NoError(types.Validate.Struct(signedMeterValue))
+
+	message, publicKey, err := DecodeSignedMeterValue(*signedMeterValue)
+	s.Require().NoError(err)
+	s.Equal(*s.message, *message)
+	s.True(s.privateKey.PublicKey.Equal(publicKey))
+
+	// Without the public key
+	signedMeterValue, err = NewSignedMeterValue(*s.message, nil)
+	s.Require().NoError(err)
+	s.Empty(signedMeterValue.PublicKey)
+
+	_, publicKey, err = DecodeSignedMeterValue(*signedMeterValue)
+	s.Require().NoError(err)
+	s.Nil(publicKey)
+}
+
+func (s *signedMeterValueTestSuite) TestDecodeSignedMeterValue_invalid() {
+	signedMeterValue, err := NewSignedMeterValue(*s.message, &s.privateKey.PublicKey)
+	s.Require().NoError(err)
+
+	invalidEncoding := *signedMeterValue
+	invalidEncoding.EncodingMethod = "EDL"
+	_, _, err = DecodeSignedMeterValue(invalidEncoding)
+	s.ErrorIs(err, ocmf.ErrUnsupportedEncodingMethod)
+
+	invalidData := *signedMeterValue
+	invalidData.SignedMeterData = "not base64"
+	_, _, err = DecodeSignedMeterValue(invalidData)
+	s.ErrorContains(err, "failed to decode signed meter data")
+
+	invalidKey := *signedMeterValue
+	invalidKey.PublicKey = base64.StdEncoding.EncodeToString([]byte("key"))
+	_, _, err = DecodeSignedMeterValue(invalidKey)
+	s.ErrorIs(err, ocmf.ErrInvalidPublicKey)
+}
+
+func (s *signedMeterValueTestSuite) TestVerifySignedMeterValue() {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	registry := ocmf.NewKeyRegistry()
+	registry.Register("exampleSerial123", &s.privateKey.PublicKey)
+
+	otherRegistry := ocmf.NewKeyRegistry()
+	otherRegistry.Register("exampleSerial123", &otherKey.PublicKey)
+
+	tests := []struct {
+		name        string
+		embeddedKey *ecdsa.PublicKey
+		resolver    ocmf.KeyResolver
+		error       error
+	}{
+		{
+			name:        "Embedded key",
+			embeddedKey: &s.privateKey.PublicKey,
+		},
+		{
+			name:     "Resolved key",
+			resolver: registry,
+		},
+		{
+			name:        "Embedded key matches resolved key",
+			embeddedKey: &s.privateKey.PublicKey,
+			resolver:    registry,
+		},
+		{
+			name:        "Embedded key does not match resolved key",
+			embeddedKey: &otherKey.PublicKey,
+			resolver:    registry,
+			error:       ocmf.ErrPublicKeyMismatch,
+		},
+		{
+			name:        "Wrong key",
+			embeddedKey: &otherKey.PublicKey,
+			error:       ocmf.ErrVerificationFailure,
+		},
+		{
+			name:     "Wrong resolved key",
+			resolver: otherRegistry,
+			error:    ocmf.ErrVerificationFailure,
+		},
+		{
+			name:     "Unknown meter",
+			resolver: ocmf.NewKeyRegistry(),
+			error:    ocmf.ErrKeyNotFound,
+		},
+		{
+			name:  "No key",
+			error: ocmf.ErrPublicKeyMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			signedMeterValue, err := NewSignedMeterValue(*s.message, tt.embeddedKey)
+			s.Require().NoError(err)
+
+			message, err := VerifySignedMeterValue(context.Background(), *signedMeterValue, tt.resolver)
+			if tt.error != nil {
+				s.ErrorIs(err, tt.error)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Equal(*s.message, *message)
+		})
+	}
+}
+
+func TestSignedMeterValue(t *testing.T) {
+	suite.Run(t, new(signedMeterValueTestSuite))
+}
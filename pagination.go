@@ -0,0 +1,196 @@
+package ocmf_go
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidPagination = errors.New("invalid pagination")
+	ErrPaginationReplay  = errors.New("pagination counter replayed")
+	ErrPaginationGap     = errors.New("pagination counter gap")
+	// ErrPaginationConflict is returned by Commit if another counter was issued since the pagination was predicted.
+	ErrPaginationConflict = errors.New("pagination counter was issued concurrently")
+)
+
+type PaginationKind string
+
+const (
+	PaginationTransaction = PaginationKind("T")
+	PaginationFiscal      = PaginationKind("F")
+)
+
+func isValidPaginationKind(kind PaginationKind) bool {
+	switch kind {
+	case PaginationTransaction, PaginationFiscal:
+		return true
+	default:
+		return false
+	}
+}
+
+// Pagination is the parsed form of the PG field: an indicator followed by a counter, e.g. "T12" or "F3".
+type Pagination struct {
+	Kind    PaginationKind
+	Counter uint64
+}
+
+func ParsePagination(pagination string) (*Pagination, error) {
+	if !indicatorNumberRegex.MatchString(pagination) {
+		return nil, errors.Wrapf(ErrInvalidPagination, "%q", pagination)
+	}
+
+	counter, err := strconv.ParseUint(pagination[1:], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidPagination, "%q", pagination)
+	}
+
+	return &Pagination{
+		Kind:    PaginationKind(pagination[:1]),
+		Counter: counter,
+	}, nil
+}
+
+func (p Pagination) String() string {
+	return fmt.Sprintf("%s%d", p.Kind, p.Counter)
+}
+
+// CheckPaginationSequence compares a received pagination with the last one seen for the same meter.
+// It returns ErrPaginationReplay if the counter did not advance and ErrPaginationGap if counters were skipped.
+func CheckPaginationSequence(previous, current Pagination) error {
+	if previous.Kind != current.Kind {
+		return errors.Wrapf(ErrInvalidPagination, "cannot compare %s with %s", previous, current)
+	}
+
+	switch {
+	case current.Counter <= previous.Counter:
+		return errors.Wrapf(ErrPaginationReplay, "received %s after %s", current, previous)
+	case current.Counter > previous.Counter+1:
+		return errors.Wrapf(ErrPaginationGap, "received %s after %s", current, previous)
+	default:
+		return nil
+	}
+}
+
+// PaginationCounters holds the last issued counters for a single meter.
+type PaginationCounters struct {
+	Transaction uint64 `json:"transaction"`
+	Fiscal      uint64 `json:"fiscal"`
+}
+
+// PaginationStore persists the pagination counters per meter serial.
+type PaginationStore interface {
+	// Load returns the counters for the meter. Unknown meters return zero counters.
+	Load(meterSerial string) (PaginationCounters, error)
+	Save(meterSerial string, counters PaginationCounters) error
+}
+
+// PaginationManager hands out monotonically increasing pagination counters per meter.
+type PaginationManager struct {
+	mu    sync.Mutex
+	store PaginationStore
+}
+
+func NewPaginationManager(store PaginationStore) *PaginationManager {
+	if store == nil {
+		store = NewMemoryPaginationStore()
+	}
+
+	return &PaginationManager{
+		store: store,
+	}
+}
+
+// Next increments and persists the counter of the given kind for the meter and returns the new pagination.
+func (m *PaginationManager) Next(meterSerial string, kind PaginationKind) (*Pagination, error) {
+	if !isValidPaginationKind(kind) {
+		return nil, fmt.Errorf("unsupported pagination kind: %s", kind)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters, err := m.store.Load(meterSerial)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load pagination counters")
+	}
+
+	next := nextPagination(counters, kind)
+	switch kind {
+	case PaginationTransaction:
+		counters.Transaction = next.Counter
+	case PaginationFiscal:
+		counters.Fiscal = next.Counter
+	}
+
+	err = m.store.Save(meterSerial, counters)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save pagination counters")
+	}
+
+	return &next, nil
+}
+
+// Commit stores the pagination as the last issued counter of the meter, if it directly follows the current counter of its kind.
+// Otherwise, it returns ErrPaginationConflict and leaves the counters unchanged, so a counter is only consumed by the
+// caller that actually used it.
+func (m *PaginationManager) Commit(meterSerial string, pagination Pagination) error {
+	if !isValidPaginationKind(pagination.Kind) {
+		return fmt.Errorf("unsupported pagination kind: %s", pagination.Kind)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters, err := m.store.Load(meterSerial)
+	if err != nil {
+		return errors.Wrap(err, "failed to load pagination counters")
+	}
+
+	expected := nextPagination(counters, pagination.Kind)
+	if pagination != expected {
+		return errors.Wrapf(ErrPaginationConflict, "expected %s, got %s", expected, pagination)
+	}
+
+	switch pagination.Kind {
+	case PaginationTransaction:
+		counters.Transaction = pagination.Counter
+	case PaginationFiscal:
+		counters.Fiscal = pagination.Counter
+	}
+
+	err = m.store.Save(meterSerial, counters)
+	if err != nil {
+		return errors.Wrap(err, "failed to save pagination counters")
+	}
+
+	return nil
+}
+
+func nextPagination(counters PaginationCounters, kind PaginationKind) Pagination {
+	switch kind {
+	case PaginationFiscal:
+		return Pagination{Kind: kind, Counter: counters.Fiscal + 1}
+	default:
+		return Pagination{Kind: kind, Counter: counters.Transaction + 1}
+	}
+}
+
+func (m *PaginationManager) NextTransaction(meterSerial string) (*Pagination, error) {
+	return m.Next(meterSerial, PaginationTransaction)
+}
+
+func (m *PaginationManager) NextFiscal(meterSerial string) (*Pagination, error) {
+	return m.Next(meterSerial, PaginationFiscal)
+}
+
+// Current returns the last issued counters for the meter without incrementing them.
+func (m *PaginationManager) Current(meterSerial string) (PaginationCounters, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.store.Load(meterSerial)
+}
@@ -0,0 +1,126 @@
+package ocmf_go
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type MemoryPaginationStore struct {
+	mu       sync.RWMutex
+	counters map[string]PaginationCounters
+}
+
+func NewMemoryPaginationStore() *MemoryPaginationStore {
+	return &MemoryPaginationStore{
+		counters: make(map[string]PaginationCounters),
+	}
+}
+
+func (s *MemoryPaginationStore) Load(meterSerial string) (PaginationCounters, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.counters[meterSerial], nil
+}
+
+func (s *MemoryPaginationStore) Save(meterSerial string, counters PaginationCounters) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters[meterSerial] = counters
+	return nil
+}
+
+// FilePaginationStore keeps the counters of all meters in a single JSON file.
+// The file is rewritten atomically on every save, so counters survive restarts.
+type FilePaginationStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFilePaginationStore(path string) *FilePaginationStore {
+	return &FilePaginationStore{
+		path: path,
+	}
+}
+
+func (s *FilePaginationStore) Load(meterSerial string) (PaginationCounters, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counters, err := s.read()
+	if err != nil {
+		return PaginationCounters{}, err
+	}
+
+	return counters[meterSerial], nil
+}
+
+func (s *FilePaginationStore) Save(meterSerial string, counters PaginationCounters) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	all[meterSerial] = counters
+	return s.write(all)
+}
+
+func (s *FilePaginationStore) read() (map[string]PaginationCounters, error) {
+	counters := make(map[string]PaginationCounters)
+
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return counters, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to read pagination file")
+	}
+
+	if len(data) == 0 {
+		return counters, nil
+	}
+
+	err = json.Unmarshal(data, &counters)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pagination file")
+	}
+
+	return counters, nil
+}
+
+func (s *FilePaginationStore) write(counters map[string]PaginationCounters) error {
+	data, err := json.MarshalIndent(counters, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal pagination counters")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary pagination file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to write pagination file")
+	}
+
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "failed to sync pagination file")
+	}
+
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to close pagination file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), s.path), "failed to replace pagination file")
+}
@@ -0,0 +1,64 @@
+package ocmf_go
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type paginationStoreTestSuite struct {
+	suite.Suite
+}
+
+func (s *paginationStoreTestSuite) TestMemoryStore() {
+	store := NewMemoryPaginationStore()
+
+	counters, err := store.Load("meter1")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{}, counters)
+
+	err = store.Save("meter1", PaginationCounters{Transaction: 3, Fiscal: 1})
+	s.Require().NoError(err)
+
+	counters, err = store.Load("meter1")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{Transaction: 3, Fiscal: 1}, counters)
+}
+
+func (s *paginationStoreTestSuite) TestFileStore() {
+	path := filepath.Join(s.T().TempDir(), "pagination.json")
+	store := NewFilePaginationStore(path)
+
+	counters, err := store.Load("meter1")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{}, counters)
+
+	err = store.Save("meter1", PaginationCounters{Transaction: 3, Fiscal: 1})
+	s.Require().NoError(err)
+	err = store.Save("meter2", PaginationCounters{Transaction: 7})
+	s.Require().NoError(err)
+
+	// Counters survive a new store instance
+	reopened := NewFilePaginationStore(path)
+	counters, err = reopened.Load("meter1")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{Transaction: 3, Fiscal: 1}, counters)
+
+	counters, err = reopened.Load("meter2")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{Transaction: 7}, counters)
+}
+
+func (s *paginationStoreTestSuite) TestFileStore_corrupted() {
+	path := filepath.Join(s.T().TempDir(), "pagination.json")
+	s.Require().NoError(os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := NewFilePaginationStore(path).Load("meter1")
+	s.ErrorContains(err, "failed to unmarshal pagination file")
+}
+
+func TestPaginationStore(t *testing.T) {
+	suite.Run(t, new(paginationStoreTestSuite))
+}
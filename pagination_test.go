@@ -0,0 +1,257 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *Pagination
+		error    bool
+	}{
+		{
+			name:     "Transaction counter",
+			input:    "T12",
+			expected: &Pagination{Kind: PaginationTransaction, Counter: 12},
+		},
+		{
+			name:     "Fiscal counter",
+			input:    "F3",
+			expected: &Pagination{Kind: PaginationFiscal, Counter: 3},
+		},
+		{
+			name:  "Missing indicator",
+			input: "12",
+			error: true,
+		},
+		{
+			name:  "Missing counter",
+			input: "T",
+			error: true,
+		},
+		{
+			name:  "Unknown indicator",
+			input: "X1",
+			error: true,
+		},
+		{
+			name:  "Counter overflow",
+			input: "T99999999999999999999999",
+			error: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination, err := ParsePagination(tt.input)
+			if tt.error {
+				assert.ErrorIs(t, err, ErrInvalidPagination)
+				assert.Nil(t, pagination)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, pagination)
+				assert.Equal(t, tt.input, pagination.String())
+			}
+		})
+	}
+}
+
+func TestCheckPaginationSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous Pagination
+		current  Pagination
+		error    error
+	}{
+		{
+			name:     "Consecutive",
+			previous: Pagination{Kind: PaginationTransaction, Counter: 1},
+			current:  Pagination{Kind: PaginationTransaction, Counter: 2},
+		},
+		{
+			name:     "Duplicate",
+			previous: Pagination{Kind: PaginationTransaction, Counter: 2},
+			current:  Pagination{Kind: PaginationTransaction, Counter: 2},
+			error:    ErrPaginationReplay,
+		},
+		{
+			name:     "Rollback",
+			previous: Pagination{Kind: PaginationFiscal, Counter: 5},
+			current:  Pagination{Kind: PaginationFiscal, Counter: 3},
+			error:    ErrPaginationReplay,
+		},
+		{
+			name:     "Gap",
+			previous: Pagination{Kind: PaginationTransaction, Counter: 2},
+			current:  Pagination{Kind: PaginationTransaction, Counter: 5},
+			error:    ErrPaginationGap,
+		},
+		{
+			name:     "Different kinds",
+			previous: Pagination{Kind: PaginationTransaction, Counter: 1},
+			current:  Pagination{Kind: PaginationFiscal, Counter: 2},
+			error:    ErrInvalidPagination,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPaginationSequence(tt.previous, tt.current)
+			if tt.error != nil {
+				assert.ErrorIs(t, err, tt.error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type paginationManagerTestSuite struct {
+	suite.Suite
+}
+
+func (s *paginationManagerTestSuite) TestNext() {
+	manager := NewPaginationManager(NewMemoryPaginationStore())
+
+	pagination, err := manager.NextTransaction("meter1")
+	s.Require().NoError(err)
+	s.Equal("T1", pagination.String())
+
+	pagination, err = manager.NextTransaction("meter1")
+	s.Require().NoError(err)
+	s.Equal("T2", pagination.String())
+
+	pagination, err = manager.NextFiscal("meter1")
+	s.Require().NoError(err)
+	s.Equal("F1", pagination.String())
+
+	// Counters are kept per meter
+	pagination, err = manager.NextTransaction("meter2")
+	s.Require().NoError(err)
+	s.Equal("T1", pagination.String())
+
+	counters, err := manager.Current("meter1")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{Transaction: 2, Fiscal: 1}, counters)
+
+	_, err = manager.Next("meter1", PaginationKind("X"))
+	s.Error(err)
+}
+
+func (s *paginationManagerTestSuite) TestNext_concurrent() {
+	manager := NewPaginationManager(nil)
+
+	wg := sync.WaitGroup{}
+	seen := sync.Map{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pagination, err := manager.NextTransaction("meter1")
+			s.NoError(err)
+
+			_, duplicate := seen.LoadOrStore(pagination.Counter, struct{}{})
+			s.False(duplicate)
+		}()
+	}
+	wg.Wait()
+
+	counters, err := manager.Current("meter1")
+	s.Require().NoError(err)
+	s.EqualValues(50, counters.Transaction)
+}
+
+func (s *paginationManagerTestSuite) TestBuilderWithPaginationManager() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	manager := NewPaginationManager(NewMemoryPaginationStore())
+	builder := NewBuilder(privateKey, WithPaginationManager(manager, PaginationTransaction)).
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 1.0,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		})
+
+	for _, expected := range []string{"T1", "T2"} {
+		message, err := builder.Build()
+		s.Require().NoError(err)
+
+		payload, err := NewParser().ParseOcmfMessageFromString(message.String()).GetPayload()
+		s.Require().NoError(err)
+		s.Equal(expected, payload.Pagination)
+	}
+
+	// An invalid payload must not consume a counter
+	builder.WithMeterSerial("")
+	_, err = builder.Build()
+	s.Error(err)
+
+	counters, err := manager.Current("exampleSerial123")
+	s.Require().NoError(err)
+	s.EqualValues(2, counters.Transaction)
+
+	// Neither does a failed or cancelled signing
+	builder.WithMeterSerial("exampleSerial123")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = builder.BuildContext(ctx)
+	s.ErrorIs(err, context.Canceled)
+
+	_, err = NewBuilder(nil, WithPaginationManager(manager, PaginationTransaction)).
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 1.0,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Build()
+	s.ErrorContains(err, "signer is required")
+
+	counters, err = manager.Current("exampleSerial123")
+	s.Require().NoError(err)
+	s.EqualValues(2, counters.Transaction)
+
+	message, err := builder.Build()
+	s.Require().NoError(err)
+	s.Equal("T3", message.Payload.Pagination)
+}
+
+func (s *paginationManagerTestSuite) TestCommit() {
+	manager := NewPaginationManager(nil)
+
+	s.Require().NoError(manager.Commit("meter1", Pagination{Kind: PaginationTransaction, Counter: 1}))
+	s.Require().NoError(manager.Commit("meter1", Pagination{Kind: PaginationFiscal, Counter: 1}))
+
+	// Only the next counter can be committed
+	s.ErrorIs(manager.Commit("meter1", Pagination{Kind: PaginationTransaction, Counter: 1}), ErrPaginationConflict)
+	s.ErrorIs(manager.Commit("meter1", Pagination{Kind: PaginationTransaction, Counter: 3}), ErrPaginationConflict)
+	s.Error(manager.Commit("meter1", Pagination{Kind: PaginationKind("X"), Counter: 2}))
+
+	pagination, err := manager.NextTransaction("meter1")
+	s.Require().NoError(err)
+	s.Equal("T2", pagination.String())
+
+	counters, err := manager.Current("meter1")
+	s.Require().NoError(err)
+	s.Equal(PaginationCounters{Transaction: 2, Fiscal: 1}, counters)
+}
+
+func TestPaginationManager(t *testing.T) {
+	suite.Run(t, new(paginationManagerTestSuite))
+}
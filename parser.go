@@ -1,8 +1,9 @@
 package ocmf_go
 
 import (
-	"encoding/json"
-	"strings"
+	"context"
+	"log/slog"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -11,13 +12,17 @@ var (
 	ErrInvalidFormat       = errors.New("invalid OCMF message format")
 	ErrVerificationFailure = errors.New("verification failed")
 	ErrPayloadEmpty        = errors.New("payload is empty")
+	// ErrUnsupportedEncodingMethod is returned for signed values of OCPP or OCPI that do not carry an OCMF message.
+	ErrUnsupportedEncodingMethod = errors.New("unsupported encoding method")
 )
 
 type Parser struct {
-	payload   *PayloadSection
-	signature *Signature
-	opts      ParserOpts
-	err       error
+	payload      *PayloadSection
+	signature    *Signature
+	rawPayload   []byte
+	rawSignature []byte
+	opts         ParserOpts
+	err          error
 }
 
 func NewParser(opts ...Opt) *Parser {
@@ -34,15 +39,28 @@ func NewParser(opts ...Opt) *Parser {
 
 // ParseOcmfMessageFromString Returns a new Parser instance with the payload and signature fields set
 func (p *Parser) ParseOcmfMessageFromString(data string) *Parser {
-	payloadSection, signature, err := parseOcmfMessageFromString(data)
+	message, err := ParseMessage(data)
 	if err != nil {
+		p.opts.hooks.meter().MessageParsed()
+		p.opts.hooks.observeInvalid(context.Background(), ReasonFormat, err)
 		return &Parser{err: err, opts: p.opts}
 	}
 
+	return p.ParseMessage(*message)
+}
+
+// ParseMessage Returns a new Parser instance for an already decoded message, e.g. one returned by the Builder
+func (p *Parser) ParseMessage(message Message) *Parser {
+	p.opts.hooks.meter().MessageParsed()
+	p.opts.hooks.log(context.Background(), slog.LevelDebug, "OCMF message parsed",
+		"meterSerial", message.Payload.MeterSerial, "pagination", message.Payload.Pagination)
+
 	return &Parser{
-		payload:   payloadSection,
-		signature: signature,
-		opts:      p.opts,
+		payload:      &message.Payload,
+		signature:    &message.Signature,
+		rawPayload:   message.RawPayload,
+		rawSignature: message.RawSignature,
+		opts:         p.opts,
 	}
 }
 
@@ -54,6 +72,7 @@ func (p *Parser) GetPayload() (*PayloadSection, error) {
 	// Validate the payload if automatic validation is enabled
 	if p.opts.withAutomaticValidation {
 		if err := p.payload.Validate(); err != nil {
+			p.opts.hooks.observeInvalid(context.Background(), ReasonValidation, err)
 			return nil, errors.Wrap(err, "payload validation failed")
 		}
 	}
@@ -62,13 +81,19 @@ func (p *Parser) GetPayload() (*PayloadSection, error) {
 }
 
 func (p *Parser) GetSignature() (*Signature, error) {
+	return p.GetSignatureContext(context.Background())
+}
+
+// GetSignatureContext is like GetSignature, but passes the context on to the key resolver.
+func (p *Parser) GetSignatureContext(ctx context.Context) (*Signature, error) {
 	if p.err != nil {
 		return nil, p.err
 	}
 
 	// Validate the signature if automatic validation is enabled
 	if p.opts.withAutomaticValidation {
-		if err := p.signature.Validate(); err != nil {
+		if err := p.signature.WithDefaults().Validate(); err != nil {
+			p.opts.hooks.observeInvalid(ctx, ReasonValidation, err)
 			return nil, errors.Wrap(err, "signature validation failed")
 		}
 	}
@@ -78,7 +103,20 @@ func (p *Parser) GetSignature() (*Signature, error) {
 			return nil, ErrPayloadEmpty
 		}
 
-		valid, err := p.signature.Verify(*p.payload, p.opts.publicKey)
+		start := time.Now()
+		publicKey := p.opts.publicKey
+		if p.opts.keyResolver != nil {
+			var err error
+			publicKey, err = SelectPublicKey(ctx, p.opts.keyResolver, p.payload.MeterSerial, p.opts.publicKey)
+			if err != nil {
+				p.opts.hooks.observeVerification(ctx, start, p.payload, false, err)
+				return nil, err
+			}
+		}
+
+		message := Message{Payload: *p.payload, RawPayload: p.rawPayload, Signature: *p.signature}
+		valid, err := message.Verify(publicKey)
+		p.opts.hooks.observeVerification(ctx, start, p.payload, valid, err)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to verify signature")
 		}
@@ -92,29 +130,55 @@ func (p *Parser) GetSignature() (*Signature, error) {
 	return p.signature, nil
 }
 
-func parseOcmfMessageFromString(data string) (*PayloadSection, *Signature, error) {
-	if !strings.HasPrefix(data, "OCMF|") {
-		return nil, nil, ErrInvalidFormat
+// GetReport verifies the message with the key or key resolver of the parser options and records the result.
+// Unlike GetSignature, a failed verification or validation is not returned as an error, but as part of the report.
+func (p *Parser) GetReport(ctx context.Context) (*VerificationReport, error) {
+	if p.err != nil {
+		return nil, p.err
 	}
 
-	data, _ = strings.CutPrefix(data, "OCMF|")
-	splitData := strings.Split(data, "|")
+	if p.payload == nil {
+		return nil, ErrPayloadEmpty
+	}
+
+	start := time.Now()
+	message := Message{Payload: *p.payload, RawPayload: p.rawPayload, Signature: *p.signature, RawSignature: p.rawSignature}
+	report := message.report(ctx, p.opts.keyResolver, p.opts.publicKey)
+	p.opts.hooks.observeVerification(ctx, start, p.payload, report.Valid(), report.cause)
+
+	return report, nil
+}
+
+// GetMessage returns the parsed message after applying the same validation and verification as GetPayload and GetSignature
+func (p *Parser) GetMessage() (*Message, error) {
+	return p.GetMessageContext(context.Background())
+}
 
-	if len(splitData) != 2 {
-		return nil, nil, ErrInvalidFormat
+// GetMessageContext is like GetMessage, but passes the context on to the key resolver.
+func (p *Parser) GetMessageContext(ctx context.Context) (*Message, error) {
+	payload, err := p.GetPayload()
+	if err != nil {
+		return nil, err
 	}
 
-	payloadSection := PayloadSection{}
-	err := json.Unmarshal([]byte(splitData[0]), &payloadSection)
+	signature, err := p.GetSignatureContext(ctx)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to unmarshal payload")
+		return nil, err
 	}
 
-	signature := Signature{}
-	err = json.Unmarshal([]byte(splitData[1]), &signature)
+	return &Message{
+		Payload:      *payload,
+		RawPayload:   p.rawPayload,
+		Signature:    *signature,
+		RawSignature: p.rawSignature,
+	}, nil
+}
+
+func parseOcmfMessageFromString(data string) (*PayloadSection, *Signature, error) {
+	message, err := ParseMessage(data)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to unmarshal signature")
+		return nil, nil, err
 	}
 
-	return &payloadSection, &signature, nil
+	return &message.Payload, &message.Signature, nil
 }
@@ -1,11 +1,16 @@
 package ocmf_go
 
-import "crypto/ecdsa"
+import (
+	"crypto/ecdsa"
+	"log/slog"
+)
 
 type ParserOpts struct {
 	withAutomaticValidation            bool
 	withAutomaticSignatureVerification bool
 	publicKey                          *ecdsa.PublicKey
+	keyResolver                        KeyResolver
+	hooks                              hooks
 }
 
 type Opt func(*ParserOpts)
@@ -23,6 +28,29 @@ func WithAutomaticSignatureVerification(publicKey *ecdsa.PublicKey) Opt {
 	}
 }
 
+// WithKeyResolver verifies the signature with the key registered for the meter serial of the payload.
+// If a public key was also given with WithAutomaticSignatureVerification, it must match the registered key.
+func WithKeyResolver(resolver KeyResolver) Opt {
+	return func(p *ParserOpts) {
+		p.withAutomaticSignatureVerification = true
+		p.keyResolver = resolver
+	}
+}
+
+// WithLogger logs failed validations and verifications as warnings and successful ones as debug messages.
+func WithLogger(logger *slog.Logger) Opt {
+	return func(p *ParserOpts) {
+		p.hooks.logger = logger
+	}
+}
+
+// WithMetrics reports the parsed, invalid and verified messages to the metrics.
+func WithMetrics(metrics Metrics) Opt {
+	return func(p *ParserOpts) {
+		p.hooks.metrics = metrics
+	}
+}
+
 func defaultOpts() ParserOpts {
 	return ParserOpts{
 		withAutomaticValidation: false,
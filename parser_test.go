@@ -69,7 +69,7 @@ func (s *parserTestSuite) TestGetSignature_valid() {
 	s.Require().NoError(err)
 
 	builder := NewBuilder(privateKey).
-		WithPagination("1").
+		WithPagination("T1").
 		WithMeterSerial("exampleSerial123").
 		WithIdentificationStatus(true).
 		WithIdentificationType(string(RfidNone)).
@@ -83,6 +83,11 @@ func (s *parserTestSuite) TestGetSignature_valid() {
 	message, err := builder.Build()
 	s.Require().NoError(err)
 
+	// The builder does not keep the signature data, so take it from the built message
+	builtSignature := &message.Signature
+	s.Equal(builder.signature.Algorithm, builtSignature.Algorithm)
+	s.Equal(builder.signature.Encoding, builtSignature.Encoding)
+
 	tests := []struct {
 		name              string
 		parserOpts        []Opt
@@ -92,24 +97,24 @@ func (s *parserTestSuite) TestGetSignature_valid() {
 		{
 			name:              "No validation",
 			parserOpts:        []Opt{},
-			data:              *message,
-			expectedSignature: &builder.signature,
+			data:              message.String(),
+			expectedSignature: builtSignature,
 		},
 		{
 			name: "With automatic signature verification",
 			parserOpts: []Opt{
 				WithAutomaticSignatureVerification(&privateKey.PublicKey),
 			},
-			data:              *message,
-			expectedSignature: &builder.signature,
+			data:              message.String(),
+			expectedSignature: builtSignature,
 		},
 		{
 			name: "With automatic payload validation",
 			parserOpts: []Opt{
 				WithAutomaticValidation(),
 			},
-			data:              *message,
-			expectedSignature: &builder.signature,
+			data:              message.String(),
+			expectedSignature: builtSignature,
 		},
 	}
 
@@ -132,7 +137,7 @@ func (s *parserTestSuite) TestGetSignature_invalid() {
 	s.Require().NoError(err)
 
 	builder := NewBuilder(privateKey).
-		WithPagination("1").
+		WithPagination("T1").
 		WithMeterSerial("exampleSerial123").
 		WithIdentificationStatus(true).
 		WithIdentificationType(string(RfidNone)).
@@ -160,14 +165,14 @@ func (s *parserTestSuite) TestGetSignature_invalid() {
 			parserOpts: []Opt{
 				WithAutomaticSignatureVerification(&privateKey2.PublicKey),
 			},
-			data:  *message,
+			data:  message.String(),
 			error: "verification failed",
 		}, {
 			name: "Nil public key",
 			parserOpts: []Opt{
 				WithAutomaticSignatureVerification(nil),
 			},
-			data:  *message,
+			data:  message.String(),
 			error: "unable to verify signature",
 		},
 		{
@@ -175,7 +180,7 @@ func (s *parserTestSuite) TestGetSignature_invalid() {
 			parserOpts: []Opt{
 				WithAutomaticSignatureVerification(&privateKey.PublicKey),
 			},
-			data:  *message,
+			data:  message.String(),
 			error: "payload is empty",
 		},
 	}
@@ -194,6 +199,40 @@ func (s *parserTestSuite) TestGetSignature_invalid() {
 	}
 }
 
+func (s *parserTestSuite) TestGetMessage() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	message, err := NewBuilder(privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 1.0,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+
+	parser := NewParser(WithAutomaticValidation(), WithAutomaticSignatureVerification(&privateKey.PublicKey))
+
+	parsed, err := parser.ParseMessage(*message).GetMessage()
+	s.Require().NoError(err)
+	s.Equal(*message, *parsed)
+
+	parsed, err = parser.ParseOcmfMessageFromString(message.String()).GetMessage()
+	s.Require().NoError(err)
+	s.Equal(*message, *parsed)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	_, err = NewParser(WithAutomaticSignatureVerification(&otherKey.PublicKey)).ParseMessage(*message).GetMessage()
+	s.ErrorIs(err, ErrVerificationFailure)
+}
+
 func TestParser(t *testing.T) {
 	suite.Run(t, new(parserTestSuite))
 }
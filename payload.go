@@ -1,7 +1,17 @@
 package ocmf_go
 
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
 const OcmfVersion = "0.4"
 
+// ReadingTimeLayout is the layout of the timestamp part of the TM field, e.g. "2018-07-24T13:22:04,000+0200".
+const ReadingTimeLayout = "2006-01-02T15:04:05,000-0700"
+
 type MeterError string
 
 const (
@@ -186,6 +196,43 @@ func isValidCurrentType(ct CurrentType) bool {
 	}
 }
 
+type TransactionType string
+
+const (
+	TransactionBegin                  = TransactionType("B")
+	TransactionCharging               = TransactionType("C")
+	TransactionException              = TransactionType("X")
+	TransactionEnd                    = TransactionType("E")
+	TransactionTerminatedLocal        = TransactionType("L")
+	TransactionTerminatedRemote       = TransactionType("R")
+	TransactionTerminatedAbort        = TransactionType("A")
+	TransactionTerminatedPowerFailure = TransactionType("P")
+	TransactionSuspended              = TransactionType("S")
+	TransactionTariffChange           = TransactionType("T")
+)
+
+func isValidTransactionType(t TransactionType) bool {
+	switch t {
+	case TransactionBegin, TransactionCharging, TransactionException, TransactionEnd,
+		TransactionTerminatedLocal, TransactionTerminatedRemote, TransactionTerminatedAbort,
+		TransactionTerminatedPowerFailure, TransactionSuspended, TransactionTariffChange:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsEnd reports whether the reading was taken at the end of the transaction, regardless of how it ended.
+func (t TransactionType) IsEnd() bool {
+	switch t {
+	case TransactionEnd, TransactionTerminatedLocal, TransactionTerminatedRemote,
+		TransactionTerminatedAbort, TransactionTerminatedPowerFailure:
+		return true
+	default:
+		return false
+	}
+}
+
 type PayloadSection struct {
 	// General information
 	FormatVersion  string `json:"FV,omitempty"`
@@ -193,7 +240,7 @@ type PayloadSection struct {
 	GatewaySerial  string `json:"GS,omitempty"`
 	GatewayVersion string `json:"GV,omitempty"`
 	// Pagination
-	Pagination string `json:"PG" validate:"required"`
+	Pagination string `json:"PG" validate:"required,pagination"`
 	// Meter identification
 	MeterVendor   string `json:"MV,omitempty"`
 	MeterModel    string `json:"MM,omitempty"`
@@ -229,7 +276,7 @@ type LossCompensation struct {
 
 type Reading struct {
 	Time              string  `json:"TM" validate:"required,iso8601"`
-	Transaction       string  `json:"TX,omitempty" validate:"omitempty,oneof=B C X E L R A P S T"`
+	Transaction       string  `json:"TX,omitempty" validate:"omitempty,transactionType"`
 	ReadingValue      float64 `json:"RV" validate:"required"`
 	ReadingIdentifier string  `json:"RI,omitempty"`
 	ReadingUnit       string  `json:"RU" validate:"required,unit"`
@@ -242,3 +289,18 @@ type Reading struct {
 func (r *Reading) Validate() error {
 	return messageValidator.Struct(r)
 }
+
+// ParseTime splits the TM field into the timestamp and the time status of the meter clock.
+func (r *Reading) ParseTime() (time.Time, TimeStatus, error) {
+	timestamp, status, found := strings.Cut(r.Time, " ")
+	if !found || !isValidTimeStatus(TimeStatus(status)) {
+		return time.Time{}, "", errors.Errorf("invalid reading time: %q", r.Time)
+	}
+
+	parsed, err := time.Parse(ReadingTimeLayout, timestamp)
+	if err != nil {
+		return time.Time{}, "", errors.Wrap(err, "invalid reading time")
+	}
+
+	return parsed, TimeStatus(status), nil
+}
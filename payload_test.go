@@ -2,6 +2,7 @@ package ocmf_go
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -403,3 +404,109 @@ func Test_isValidCurrentType(t *testing.T) {
 		})
 	}
 }
+
+func TestReading_ParseTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     string
+		expected time.Time
+		status   TimeStatus
+		error    bool
+	}{
+		{
+			name:     "Synchronized",
+			time:     "2018-07-24T13:22:04,000+0200 S",
+			expected: time.Date(2018, 7, 24, 11, 22, 4, 0, time.UTC),
+			status:   TimeStatusSynchronized,
+		},
+		{
+			name:     "Milliseconds",
+			time:     "2018-07-24T13:22:04,250+0000 I",
+			expected: time.Date(2018, 7, 24, 13, 22, 4, 250_000_000, time.UTC),
+			status:   TimeStatusInformative,
+		},
+		{
+			name:  "Missing status",
+			time:  "2018-07-24T13:22:04,000+0200",
+			error: true,
+		},
+		{
+			name:  "Invalid status",
+			time:  "2018-07-24T13:22:04,000+0200 X",
+			error: true,
+		},
+		{
+			name:  "Invalid timestamp",
+			time:  "2021-01-01T00:00:00Z S",
+			error: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reading := Reading{Time: test.time}
+			parsed, status, err := reading.ParseTime()
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, test.expected.Equal(parsed))
+			assert.Equal(t, test.status, status)
+		})
+	}
+}
+
+func Test_isValidTransactionType(t *testing.T) {
+	tests := []struct {
+		name  string
+		tt    TransactionType
+		want  bool
+		isEnd bool
+	}{
+		{
+			name: "Begin",
+			tt:   TransactionBegin,
+			want: true,
+		},
+		{
+			name: "Charging",
+			tt:   TransactionCharging,
+			want: true,
+		},
+		{
+			name:  "End",
+			tt:    TransactionEnd,
+			want:  true,
+			isEnd: true,
+		},
+		{
+			name:  "Terminated by power failure",
+			tt:    TransactionTerminatedPowerFailure,
+			want:  true,
+			isEnd: true,
+		},
+		{
+			name: "Tariff change",
+			tt:   TransactionTariffChange,
+			want: true,
+		},
+		{
+			name: "invalid",
+			tt:   TransactionType("invalid"),
+			want: false,
+		},
+		{
+			name: "Empty",
+			tt:   TransactionType(""),
+			want: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, isValidTransactionType(test.tt))
+			assert.Equal(t, test.isEnd, test.tt.IsEnd())
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package receipt
+
+import ocmf "github.com/ChargePi/ocmf-go"
+
+type Language = ocmf.Language
+
+const (
+	English = ocmf.LanguageEnglish
+	German  = ocmf.LanguageGerman
+)
+
+var labels = map[Language]map[string]string{
+	English: {
+		"title":              "Charging receipt",
+		"meterSerial":        "Meter serial",
+		"meterVendor":        "Meter vendor",
+		"meterModel":         "Meter model",
+		"pagination":         "Pagination",
+		"identificationType": "Identification",
+		"identificationData": "Identification data",
+		"chargePoint":        "Charge point",
+		"tariff":             "Tariff",
+		"readings":           "Readings",
+		"time":               "Time",
+		"timeStatus":         "Time status",
+		"transaction":        "Transaction",
+		"value":              "Value",
+		"status":             "Meter status",
+		"consumption":        "Consumption",
+		"verified":           "Signature verified",
+		"invalid":            "Signature invalid",
+		"unverified":         "Signature not verified",
+	},
+	German: {
+		"title":              "Ladebeleg",
+		"meterSerial":        "Zählerseriennummer",
+		"meterVendor":        "Zählerhersteller",
+		"meterModel":         "Zählermodell",
+		"pagination":         "Paginierung",
+		"identificationType": "Identifikation",
+		"identificationData": "Identifikationsdaten",
+		"chargePoint":        "Ladepunkt",
+		"tariff":             "Tarif",
+		"readings":           "Messwerte",
+		"time":               "Zeit",
+		"timeStatus":         "Zeitstatus",
+		"transaction":        "Transaktion",
+		"value":              "Wert",
+		"status":             "Zählerstatus",
+		"consumption":        "Verbrauch",
+		"verified":           "Signatur geprüft",
+		"invalid":            "Signatur ungültig",
+		"unverified":         "Signatur nicht geprüft",
+	},
+}
+
+func label(language Language, key string) string {
+	catalog, found := labels[language]
+	if !found {
+		catalog = labels[English]
+	}
+
+	return catalog[key]
+}
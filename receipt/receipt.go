@@ -0,0 +1,218 @@
+package receipt
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNoPayloads          = errors.New("at least one payload is required")
+	ErrMeterSerialMismatch = errors.New("payloads are from different meters")
+	ErrUnsupportedFormat   = errors.New("unsupported receipt format")
+)
+
+type Format string
+
+const (
+	FormatText     = Format("text")
+	FormatMarkdown = Format("markdown")
+	FormatHTML     = Format("html")
+)
+
+// Verification is the outcome of the signature verification shown on the receipt.
+type Verification string
+
+const (
+	VerificationNone    = Verification("unverified")
+	VerificationValid   = Verification("verified")
+	VerificationInvalid = Verification("invalid")
+)
+
+// Receipt is a human-readable summary of a single message or of the begin and end messages of a charging session.
+type Receipt struct {
+	payloads     []ocmf.PayloadSection
+	verification Verification
+}
+
+// New creates a receipt for a single payload.
+func New(payload ocmf.PayloadSection, verification Verification) *Receipt {
+	return &Receipt{
+		payloads:     []ocmf.PayloadSection{payload},
+		verification: verification,
+	}
+}
+
+// NewSession creates a receipt for the begin and end payloads of a session, including the consumption.
+func NewSession(begin, end ocmf.PayloadSection, verification Verification) (*Receipt, error) {
+	if begin.MeterSerial != end.MeterSerial {
+		return nil, errors.Wrapf(ErrMeterSerialMismatch, "%s and %s", begin.MeterSerial, end.MeterSerial)
+	}
+
+	return &Receipt{
+		payloads:     []ocmf.PayloadSection{begin, end},
+		verification: verification,
+	}, nil
+}
+
+// FromMessages creates a receipt for one message or a begin/end pair, e.g. after verifying them with the parser.
+func FromMessages(verification Verification, messages ...ocmf.Message) (*Receipt, error) {
+	switch len(messages) {
+	case 0:
+		return nil, ErrNoPayloads
+	case 1:
+		return New(messages[0].Payload, verification), nil
+	default:
+		return NewSession(messages[0].Payload, messages[len(messages)-1].Payload, verification)
+	}
+}
+
+// Render writes the receipt in the format with labels in the language. Unknown languages fall back to English.
+func (r *Receipt) Render(w io.Writer, format Format, language Language) error {
+	view := r.view(language)
+
+	switch format {
+	case FormatText:
+		return renderText(w, view)
+	case FormatMarkdown:
+		return renderMarkdown(w, view)
+	case FormatHTML:
+		return renderHTML(w, view)
+	default:
+		return errors.Wrapf(ErrUnsupportedFormat, "%q", format)
+	}
+}
+
+func (r *Receipt) String() string {
+	builder := strings.Builder{}
+	_ = r.Render(&builder, FormatText, English)
+	return builder.String()
+}
+
+type field struct {
+	Label string
+	Value string
+}
+
+type readingView struct {
+	Time        string
+	TimeStatus  string
+	Transaction string
+	Value       string
+	Status      string
+}
+
+type receiptView struct {
+	Title        string
+	Verification Verification
+	Badge        string
+	Fields       []field
+	Headers      readingView
+	Readings     []readingView
+	Consumption  *field
+}
+
+func (r *Receipt) view(language Language) receiptView {
+	first := r.payloads[0]
+
+	view := receiptView{
+		Title:        label(language, "title"),
+		Verification: r.verification,
+		Badge:        label(language, string(r.verification)),
+		Headers: readingView{
+			Time:        label(language, "time"),
+			TimeStatus:  label(language, "timeStatus"),
+			Transaction: label(language, "transaction"),
+			Value:       label(language, "value"),
+			Status:      label(language, "status"),
+		},
+	}
+	if view.Badge == "" {
+		view.Verification = VerificationNone
+		view.Badge = label(language, string(VerificationNone))
+	}
+
+	paginations := []string{}
+	for _, payload := range r.payloads {
+		paginations = append(paginations, payload.Pagination)
+	}
+
+	addField := func(key, value string) {
+		if value != "" {
+			view.Fields = append(view.Fields, field{Label: label(language, key), Value: value})
+		}
+	}
+
+	addField("meterSerial", first.MeterSerial)
+	addField("meterVendor", first.MeterVendor)
+	addField("meterModel", first.MeterModel)
+	addField("pagination", strings.Join(paginations, ", "))
+	addField("identificationType", ocmf.RfidState(first.IdentificationType).Describe(language))
+	addField("identificationData", first.IdentificationData)
+	addField("chargePoint", first.ChargePointIdentification)
+	addField("tariff", first.TariffText)
+
+	for _, payload := range r.payloads {
+		for _, reading := range payload.Readings {
+			view.Readings = append(view.Readings, newReadingView(language, reading))
+		}
+	}
+
+	if consumption, found := r.consumption(language); found {
+		view.Consumption = &field{Label: label(language, "consumption"), Value: consumption}
+	}
+
+	return view
+}
+
+func newReadingView(language Language, reading ocmf.Reading) readingView {
+	view := readingView{
+		Time:        reading.Time,
+		Transaction: ocmf.TransactionType(reading.Transaction).Describe(language),
+		Value:       formatValue(language, reading.ReadingValue, reading.ReadingUnit),
+		Status:      ocmf.MeterError(reading.Status).Describe(language),
+	}
+
+	timestamp, timeStatus, err := reading.ParseTime()
+	if err == nil {
+		view.Time = timestamp.Format("2006-01-02 15:04:05 -0700")
+		view.TimeStatus = timeStatus.Describe(language)
+	}
+
+	return view
+}
+
+// consumption is the difference between the first reading of the session and the last reading
+// of the same register. Single messages have no consumption.
+func (r *Receipt) consumption(language Language) (string, bool) {
+	if len(r.payloads) < 2 {
+		return "", false
+	}
+
+	begin, end := r.payloads[0].Readings, r.payloads[len(r.payloads)-1].Readings
+	if len(begin) == 0 || len(end) == 0 {
+		return "", false
+	}
+
+	first := begin[0]
+	for i := len(end) - 1; i >= 0; i-- {
+		last := end[i]
+		if last.ReadingIdentifier == first.ReadingIdentifier && last.ReadingUnit == first.ReadingUnit {
+			return formatValue(language, last.ReadingValue-first.ReadingValue, first.ReadingUnit), true
+		}
+	}
+
+	return "", false
+}
+
+func formatValue(language Language, value float64, unit string) string {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if language == German {
+		formatted = strings.Replace(formatted, ".", ",", 1)
+	}
+
+	return strings.TrimSpace(formatted + " " + unit)
+}
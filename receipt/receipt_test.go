@@ -0,0 +1,118 @@
+package receipt
+
+import (
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type receiptTestSuite struct {
+	suite.Suite
+	begin ocmf.PayloadSection
+	end   ocmf.PayloadSection
+}
+
+func (s *receiptTestSuite) SetupTest() {
+	s.begin = ocmf.PayloadSection{
+		Pagination:         "T1",
+		MeterVendor:        "ABL",
+		MeterSerial:        "exampleSerial123",
+		IdentificationType: string(ocmf.RfidPlain),
+		IdentificationData: "1F2D3A4B",
+		Readings: []ocmf.Reading{
+			{
+				Time:         "2018-07-24T13:22:04,000+0200 S",
+				Transaction:  string(ocmf.TransactionBegin),
+				ReadingValue: 2935.6,
+				ReadingUnit:  string(ocmf.UnitskWh),
+				Status:       string(ocmf.MeterOk),
+			},
+		},
+	}
+
+	s.end = s.begin
+	s.end.Pagination = "T2"
+	s.end.Readings = []ocmf.Reading{
+		{
+			Time:         "2018-07-24T13:26:04,000+0200 S",
+			Transaction:  string(ocmf.TransactionEnd),
+			ReadingValue: 2965.6,
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		},
+	}
+}
+
+func (s *receiptTestSuite) TestNewSession() {
+	receipt, err := NewSession(s.begin, s.end, VerificationValid)
+	s.Require().NoError(err)
+
+	view := receipt.view(English)
+	s.Equal("Charging receipt", view.Title)
+	s.Equal("Signature verified", view.Badge)
+	s.Contains(view.Fields, field{Label: "Pagination", Value: "T1, T2"})
+	s.Contains(view.Fields, field{Label: "Identification", Value: "RFID card"})
+	s.Require().Len(view.Readings, 2)
+	s.Equal(readingView{
+		Time:        "2018-07-24 13:26:04 +0200",
+		TimeStatus:  "Synchronized",
+		Transaction: "Transaction end",
+		Value:       "2965.6 kWh",
+		Status:      "OK",
+	}, view.Readings[1])
+	s.Require().NotNil(view.Consumption)
+	s.Equal("30 kWh", view.Consumption.Value)
+
+	other := s.end
+	other.MeterSerial = "otherSerial"
+	_, err = NewSession(s.begin, other, VerificationValid)
+	s.ErrorIs(err, ErrMeterSerialMismatch)
+}
+
+func (s *receiptTestSuite) TestViewGerman() {
+	s.end.Readings[0].ReadingValue = 2936.1
+	receipt, err := NewSession(s.begin, s.end, VerificationInvalid)
+	s.Require().NoError(err)
+
+	view := receipt.view(German)
+	s.Equal("Ladebeleg", view.Title)
+	s.Equal("Signatur ungültig", view.Badge)
+	s.Contains(view.Fields, field{Label: "Identifikation", Value: "RFID-Karte"})
+	s.Equal("Transaktionsbeginn", view.Readings[0].Transaction)
+	s.Equal("2935,6 kWh", view.Readings[0].Value)
+	s.Equal("Synchronisiert", view.Readings[0].TimeStatus)
+	s.Equal("0,5 kWh", view.Consumption.Value)
+}
+
+func (s *receiptTestSuite) TestViewFallbacks() {
+	s.begin.Readings[0].Status = "Z"
+	s.begin.Readings[0].Time = "invalid"
+	receipt := New(s.begin, "")
+
+	view := receipt.view("fr")
+	s.Equal("Charging receipt", view.Title)
+	s.Equal(VerificationNone, view.Verification)
+	s.Equal("Signature not verified", view.Badge)
+	s.Equal("Z", view.Readings[0].Status)
+	s.Equal("invalid", view.Readings[0].Time)
+	s.Empty(view.Readings[0].TimeStatus)
+	s.Nil(view.Consumption)
+}
+
+func (s *receiptTestSuite) TestFromMessages() {
+	_, err := FromMessages(VerificationValid)
+	s.ErrorIs(err, ErrNoPayloads)
+
+	receipt, err := FromMessages(VerificationValid, ocmf.Message{Payload: s.begin})
+	s.Require().NoError(err)
+	s.Len(receipt.payloads, 1)
+
+	receipt, err = FromMessages(VerificationValid, ocmf.Message{Payload: s.begin}, ocmf.Message{Payload: s.end})
+	s.Require().NoError(err)
+	s.Len(receipt.payloads, 2)
+}
+
+func TestReceipt(t *testing.T) {
+	suite.Run(t, new(receiptTestSuite))
+}
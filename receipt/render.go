@@ -0,0 +1,95 @@
+package receipt
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+func renderText(w io.Writer, view receiptView) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, view.Title)
+	_, _ = fmt.Fprintln(tw, strings.Repeat("=", len([]rune(view.Title))))
+	_, _ = fmt.Fprintf(tw, "[%s]\n\n", view.Badge)
+
+	for _, field := range view.Fields {
+		_, _ = fmt.Fprintf(tw, "%s:\t%s\n", field.Label, field.Value)
+	}
+	_, _ = fmt.Fprintln(tw)
+
+	for _, reading := range append([]readingView{view.Headers}, view.Readings...) {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			reading.Time, reading.TimeStatus, reading.Transaction, reading.Value, reading.Status)
+	}
+
+	if view.Consumption != nil {
+		_, _ = fmt.Fprintf(tw, "\n%s:\t%s\n", view.Consumption.Label, view.Consumption.Value)
+	}
+
+	return errors.Wrap(tw.Flush(), "failed to write receipt")
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "<", `\<`, ">", `\>`, "*", `\*`, "_", `\_`, "`", "\\`", "\n", " ")
+
+func renderMarkdown(w io.Writer, view receiptView) error {
+	builder := strings.Builder{}
+
+	fmt.Fprintf(&builder, "# %s\n\n", markdownEscaper.Replace(view.Title))
+	fmt.Fprintf(&builder, "**%s**\n\n", markdownEscaper.Replace(view.Badge))
+
+	builder.WriteString("| | |\n|---|---|\n")
+	for _, field := range view.Fields {
+		fmt.Fprintf(&builder, "| %s | %s |\n", markdownEscaper.Replace(field.Label), markdownEscaper.Replace(field.Value))
+	}
+
+	builder.WriteString("\n")
+	for i, reading := range append([]readingView{view.Headers}, view.Readings...) {
+		fmt.Fprintf(&builder, "| %s | %s | %s | %s | %s |\n",
+			markdownEscaper.Replace(reading.Time),
+			markdownEscaper.Replace(reading.TimeStatus),
+			markdownEscaper.Replace(reading.Transaction),
+			markdownEscaper.Replace(reading.Value),
+			markdownEscaper.Replace(reading.Status))
+
+		if i == 0 {
+			builder.WriteString("|---|---|---|---|---|\n")
+		}
+	}
+
+	if view.Consumption != nil {
+		fmt.Fprintf(&builder, "\n**%s:** %s\n",
+			markdownEscaper.Replace(view.Consumption.Label), markdownEscaper.Replace(view.Consumption.Value))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return errors.Wrap(err, "failed to write receipt")
+}
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<div class="ocmf-receipt">
+<h1>{{.Title}}</h1>
+<p class="badge badge-{{.Verification}}">{{.Badge}}</p>
+<table class="ocmf-receipt-fields">
+{{- range .Fields}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+<table class="ocmf-receipt-readings">
+<tr><th>{{.Headers.Time}}</th><th>{{.Headers.TimeStatus}}</th><th>{{.Headers.Transaction}}</th><th>{{.Headers.Value}}</th><th>{{.Headers.Status}}</th></tr>
+{{- range .Readings}}
+<tr><td>{{.Time}}</td><td>{{.TimeStatus}}</td><td>{{.Transaction}}</td><td>{{.Value}}</td><td>{{.Status}}</td></tr>
+{{- end}}
+</table>
+{{- with .Consumption}}
+<p class="ocmf-receipt-consumption"><strong>{{.Label}}:</strong> {{.Value}}</p>
+{{- end}}
+</div>
+`))
+
+func renderHTML(w io.Writer, view receiptView) error {
+	return errors.Wrap(htmlTemplate.Execute(w, view), "failed to write receipt")
+}
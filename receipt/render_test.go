@@ -0,0 +1,71 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type renderTestSuite struct {
+	suite.Suite
+	receipt *Receipt
+}
+
+func (s *renderTestSuite) SetupTest() {
+	s.receipt = New(ocmf.PayloadSection{
+		Pagination:         "T1",
+		MeterSerial:        "exampleSerial123",
+		IdentificationType: string(ocmf.RfidNone),
+		TariffText:         "<b>0,49 €/kWh</b> | flat",
+		Readings: []ocmf.Reading{
+			{
+				Time:         "2018-07-24T13:22:04,000+0200 S",
+				Transaction:  string(ocmf.TransactionBegin),
+				ReadingValue: 2935.6,
+				ReadingUnit:  string(ocmf.UnitskWh),
+				Status:       string(ocmf.MeterOk),
+			},
+		},
+	}, VerificationValid)
+}
+
+func (s *renderTestSuite) render(format Format, language Language) string {
+	builder := strings.Builder{}
+	s.Require().NoError(s.receipt.Render(&builder, format, language))
+	return builder.String()
+}
+
+func (s *renderTestSuite) TestText() {
+	text := s.render(FormatText, English)
+	s.True(strings.HasPrefix(text, "Charging receipt\n================\n[Signature verified]\n"))
+	s.Contains(text, "Meter serial:    exampleSerial123\n")
+	s.Contains(text, "2018-07-24 13:22:04 +0200  Synchronized  Transaction begin  2935.6 kWh  OK")
+	s.Equal(text, s.receipt.String())
+}
+
+func (s *renderTestSuite) TestMarkdown() {
+	markdown := s.render(FormatMarkdown, German)
+	s.Contains(markdown, "# Ladebeleg\n\n**Signatur geprüft**\n")
+	s.Contains(markdown, "| Tarif | \\<b\\>0,49 €/kWh\\</b\\> \\| flat |\n")
+	s.Contains(markdown, "| Zeit | Zeitstatus | Transaktion | Wert | Zählerstatus |\n|---|---|---|---|---|\n")
+	s.Contains(markdown, "| 2018-07-24 13:22:04 +0200 | Synchronisiert | Transaktionsbeginn | 2935,6 kWh | In Ordnung |\n")
+}
+
+func (s *renderTestSuite) TestHTML() {
+	html := s.render(FormatHTML, English)
+	s.Contains(html, `<p class="badge badge-verified">Signature verified</p>`)
+	s.Contains(html, "<tr><th>Tariff</th><td>&lt;b&gt;0,49 €/kWh&lt;/b&gt; | flat</td></tr>")
+	s.Contains(html, "<td>Transaction begin</td>")
+	s.NotContains(html, "ocmf-receipt-consumption")
+}
+
+func (s *renderTestSuite) TestUnsupportedFormat() {
+	err := s.receipt.Render(&strings.Builder{}, "pdf", English)
+	s.ErrorIs(err, ErrUnsupportedFormat)
+}
+
+func TestRender(t *testing.T) {
+	suite.Run(t, new(renderTestSuite))
+}
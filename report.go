@@ -0,0 +1,238 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReportHashAlgorithm is the hash of the signed bytes in a VerificationReport, which is also the hash all
+// supported signature algorithms use.
+const ReportHashAlgorithm = "SHA-256"
+
+type Verdict string
+
+const (
+	// VerdictValid is set if the signature matches the signed bytes and the public key.
+	VerdictValid = Verdict("VALID")
+	// VerdictInvalid is set if the signature does not match.
+	VerdictInvalid = Verdict("INVALID")
+	// VerdictError is set if the signature could not be checked, e.g. because the key is unknown or the signature cannot be decoded.
+	VerdictError = Verdict("ERROR")
+)
+
+type KeySource string
+
+const (
+	// KeySourceProvided is set if the key was passed by the caller, e.g. read from the label of the meter.
+	KeySourceProvided = KeySource("PROVIDED")
+	// KeySourceResolver is set if the key was looked up by the meter serial.
+	KeySourceResolver = KeySource("RESOLVER")
+)
+
+// ReadingTime is the time of a single reading as stated by the meter.
+type ReadingTime struct {
+	// Time is the TM field as signed.
+	Time        string          `json:"time"`
+	Timestamp   *time.Time      `json:"timestamp,omitempty"`
+	Status      TimeStatus      `json:"status,omitempty"`
+	Transaction TransactionType `json:"transaction,omitempty"`
+}
+
+// VerificationReport records how a message was verified, so the result can be archived and reproduced.
+type VerificationReport struct {
+	Verdict Verdict `json:"verdict"`
+	// Error is the reason the signature could not be checked, if the verdict is VerdictError.
+	Error       string `json:"error,omitempty"`
+	MeterSerial string `json:"meterSerial"`
+	Pagination  string `json:"pagination"`
+
+	Algorithm SignatureAlgorithm `json:"algorithm"`
+	Curve     string             `json:"curve,omitempty"`
+	Encoding  SignatureEncoding  `json:"encoding"`
+	MimeType  SignatureMimeType  `json:"mimeType"`
+	// HashAlgorithm and PayloadHash identify the exact bytes the signature was checked against.
+	HashAlgorithm string `json:"hashAlgorithm"`
+	PayloadHash   string `json:"payloadHash"`
+	Signature     string `json:"signature"`
+
+	KeyFingerprint string    `json:"keyFingerprint,omitempty"`
+	KeySource      KeySource `json:"keySource,omitempty"`
+
+	Readings []ReadingTime `json:"readings"`
+	// ValidationErrors are reported, but do not change the verdict.
+	ValidationErrors []FieldError `json:"validationErrors,omitempty"`
+	Warnings         []string     `json:"warnings,omitempty"`
+	CreatedAt        time.Time    `json:"createdAt"`
+
+	// cause is the error behind Error, which is lost when the report is encoded
+	cause error
+}
+
+// Valid returns true if the signature was checked and matches.
+func (r *VerificationReport) Valid() bool {
+	return r.Verdict == VerdictValid
+}
+
+// Report verifies the message with the given key and records the result.
+func (m *Message) Report(publicKey *ecdsa.PublicKey) *VerificationReport {
+	return m.report(context.Background(), nil, publicKey)
+}
+
+// ReportContext is like Report, but looks up the key of the meter with the resolver.
+func (m *Message) ReportContext(ctx context.Context, resolver KeyResolver) *VerificationReport {
+	if resolver == nil {
+		report := m.newReport()
+		report.fail(errors.New("key resolver is required"))
+		return report
+	}
+
+	return m.report(ctx, resolver, nil)
+}
+
+// report uses the key of the resolver if one is given, which the provided key must match. Otherwise, the provided key is used.
+func (m *Message) report(ctx context.Context, resolver KeyResolver, publicKey *ecdsa.PublicKey) *VerificationReport {
+	report := m.newReport()
+
+	source := KeySourceProvided
+	if resolver != nil {
+		source = KeySourceResolver
+	}
+
+	publicKey, err := SelectPublicKey(ctx, resolver, m.Payload.MeterSerial, publicKey)
+	if err != nil {
+		report.KeySource = source
+		report.fail(err)
+		return report
+	}
+
+	m.verifyReport(report, publicKey, source)
+	return report
+}
+
+func (m *Message) newReport() *VerificationReport {
+	signedBytes := m.RawPayload
+	if len(signedBytes) == 0 {
+		signedBytes, _ = json.Marshal(m.Payload)
+	}
+
+	hash := sha256.Sum256(signedBytes)
+	signature := m.Signature.WithDefaults()
+	report := &VerificationReport{
+		MeterSerial:      m.Payload.MeterSerial,
+		Pagination:       m.Payload.Pagination,
+		Algorithm:        signature.Algorithm,
+		Encoding:         signature.Encoding,
+		MimeType:         signature.MimeType,
+		HashAlgorithm:    ReportHashAlgorithm,
+		PayloadHash:      hex.EncodeToString(hash[:]),
+		Signature:        m.Signature.Data,
+		Readings:         make([]ReadingTime, 0, len(m.Payload.Readings)),
+		ValidationErrors: FieldErrors(m.Validate()),
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	if curve, err := curveForAlgorithm(signature.Algorithm); err == nil {
+		report.Curve = curve.Params().Name
+	}
+
+	if len(m.RawPayload) == 0 {
+		report.Warnings = append(report.Warnings, "the payload was re-encoded, the signed bytes are not known")
+	}
+
+	report.Warnings = append(report.Warnings, m.signatureDefaultWarnings()...)
+
+	for i, reading := range m.Payload.Readings {
+		readingTime := ReadingTime{Time: reading.Time, Transaction: TransactionType(reading.Transaction)}
+
+		timestamp, status, err := reading.ParseTime()
+		if err == nil {
+			readingTime.Timestamp = &timestamp
+			readingTime.Status = status
+		}
+
+		report.Readings = append(report.Readings, readingTime)
+		report.Warnings = append(report.Warnings, readingWarnings(i, reading, status, err)...)
+	}
+
+	return report
+}
+
+// signatureDefaultWarnings reports the signature fields that were not transmitted and are assumed, see Signature.WithDefaults.
+func (m *Message) signatureDefaultWarnings() []string {
+	signature := m.Signature.WithDefaults()
+
+	var warnings []string
+	defaults := []struct {
+		field    string
+		received string
+		assumed  string
+	}{
+		{"SA", string(m.Signature.Algorithm), string(signature.Algorithm)},
+		{"SE", string(m.Signature.Encoding), string(signature.Encoding)},
+		{"SM", string(m.Signature.MimeType), string(signature.MimeType)},
+	}
+
+	for _, d := range defaults {
+		if d.received == "" {
+			warnings = append(warnings, fmt.Sprintf("%s is not present, %s is assumed", d.field, d.assumed))
+		}
+	}
+
+	return warnings
+}
+
+func readingWarnings(index int, reading Reading, status TimeStatus, timeErr error) []string {
+	var warnings []string
+
+	switch {
+	case timeErr != nil:
+		warnings = append(warnings, fmt.Sprintf("RD[%d].TM cannot be parsed: %q", index, reading.Time))
+	case status != TimeStatusSynchronized:
+		warnings = append(warnings, fmt.Sprintf("RD[%d].TM the meter clock is not synchronized: %s", index, status.Describe(LanguageEnglish)))
+	}
+
+	if reading.Status != "" && MeterError(reading.Status) != MeterOk {
+		warnings = append(warnings, fmt.Sprintf("RD[%d].ST the meter reports %s", index, MeterError(reading.Status).Describe(LanguageEnglish)))
+	}
+
+	if reading.ErrorFlags != "" {
+		warnings = append(warnings, fmt.Sprintf("RD[%d].EF the meter reports error flags %q", index, reading.ErrorFlags))
+	}
+
+	return warnings
+}
+
+func (m *Message) verifyReport(report *VerificationReport, publicKey *ecdsa.PublicKey, source KeySource) {
+	report.KeySource = source
+
+	fingerprint, err := KeyFingerprint(publicKey)
+	if err != nil {
+		report.fail(err)
+		return
+	}
+
+	report.KeyFingerprint = fingerprint
+
+	valid, err := m.Verify(publicKey)
+	switch {
+	case err != nil:
+		report.fail(err)
+	case valid:
+		report.Verdict = VerdictValid
+	default:
+		report.Verdict = VerdictInvalid
+	}
+}
+
+func (r *VerificationReport) fail(err error) {
+	r.Verdict = VerdictError
+	r.Error = err.Error()
+	r.cause = err
+}
@@ -0,0 +1,216 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type reportTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+}
+
+func (s *reportTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+}
+
+func (s *reportTestSuite) buildMessage(readings ...Reading) *Message {
+	builder := NewBuilder(s.privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone))
+
+	for _, reading := range readings {
+		builder.AddReading(reading)
+	}
+
+	message, err := builder.Build()
+	s.Require().NoError(err)
+	return message
+}
+
+func (s *reportTestSuite) TestReport_valid() {
+	message := s.buildMessage(Reading{
+		Time:         "2018-07-24T13:22:04,000+0200 S",
+		Transaction:  string(TransactionBegin),
+		ReadingValue: 1,
+		ReadingUnit:  string(UnitskWh),
+		Status:       string(MeterOk),
+	})
+
+	report := message.Report(&s.privateKey.PublicKey)
+	s.True(report.Valid())
+	s.Equal(VerdictValid, report.Verdict)
+	s.Empty(report.Error)
+	s.Equal("exampleSerial123", report.MeterSerial)
+	s.Equal("T1", report.Pagination)
+	s.Equal(SignatureAlgorithmECDSAsecp256r1SHA256, report.Algorithm)
+	s.Equal("P-256", report.Curve)
+	s.Equal(SignatureEncodingHex, report.Encoding)
+	s.Equal(SignatureMimeTypeDer, report.MimeType)
+	s.Equal(message.Signature.Data, report.Signature)
+	s.Equal(KeySourceProvided, report.KeySource)
+	s.Empty(report.Warnings)
+	s.Empty(report.ValidationErrors)
+
+	hash := sha256.Sum256(message.RawPayload)
+	s.Equal(ReportHashAlgorithm, report.HashAlgorithm)
+	s.Equal(hex.EncodeToString(hash[:]), report.PayloadHash)
+
+	fingerprint, err := KeyFingerprint(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.Equal(fingerprint, report.KeyFingerprint)
+
+	s.Require().Len(report.Readings, 1)
+	s.Equal(TimeStatusSynchronized, report.Readings[0].Status)
+	s.Equal(TransactionBegin, report.Readings[0].Transaction)
+	s.Require().NotNil(report.Readings[0].Timestamp)
+	s.Equal("2018-07-24T11:22:04Z", report.Readings[0].Timestamp.UTC().Format("2006-01-02T15:04:05Z07:00"))
+
+	data, err := json.Marshal(report)
+	s.Require().NoError(err)
+
+	decoded := map[string]any{}
+	s.Require().NoError(json.Unmarshal(data, &decoded))
+	s.Equal("VALID", decoded["verdict"])
+	s.Equal(fingerprint, decoded["keyFingerprint"])
+	s.Equal("PROVIDED", decoded["keySource"])
+	s.Equal("2018-07-24T13:22:04+02:00", decoded["readings"].([]any)[0].(map[string]any)["timestamp"])
+	s.NotContains(decoded, "warnings")
+}
+
+func (s *reportTestSuite) TestReport_invalid() {
+	message := s.buildMessage(Reading{
+		Time:         "2018-07-24T13:22:04,000+0200 S",
+		ReadingValue: 1,
+		ReadingUnit:  string(UnitskWh),
+		Status:       string(MeterOk),
+	})
+
+	tampered, err := ParseMessage(strings.Replace(message.String(), `"RV":1`, `"RV":2`, 1))
+	s.Require().NoError(err)
+
+	report := tampered.Report(&s.privateKey.PublicKey)
+	s.False(report.Valid())
+	s.Equal(VerdictInvalid, report.Verdict)
+	s.NotEqual(message.Report(&s.privateKey.PublicKey).PayloadHash, report.PayloadHash)
+
+	report = message.Report(nil)
+	s.Equal(VerdictError, report.Verdict)
+	s.Equal(ErrPublicKeyMissing.Error(), report.Error)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	s.Require().NoError(err)
+
+	report = message.Report(&otherKey.PublicKey)
+	s.Equal(VerdictError, report.Verdict)
+	s.Contains(report.Error, ErrKeyAlgorithmMismatch.Error())
+	s.NotEmpty(report.KeyFingerprint)
+}
+
+func (s *reportTestSuite) TestReport_warnings() {
+	message := s.buildMessage(
+		Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 U",
+			ReadingValue: 1,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterTimeout),
+			ErrorFlags:   "t",
+		},
+	)
+
+	// Only the signature data, the other fields are assumed
+	parsed, err := ParseMessage("OCMF|" + string(message.RawPayload) + `|{"SD":"` + message.Signature.Data + `"}`)
+	s.Require().NoError(err)
+
+	report := parsed.Report(&s.privateKey.PublicKey)
+	s.Equal(VerdictValid, report.Verdict)
+	s.Equal([]string{
+		"SA is not present, ECDSA-secp256r1-SHA256 is assumed",
+		"SE is not present, hex is assumed",
+		"SM is not present, application/x-der is assumed",
+		"RD[0].TM the meter clock is not synchronized: Unknown",
+		"RD[0].ST the meter reports Timeout",
+		`RD[0].EF the meter reports error flags "t"`,
+	}, report.Warnings)
+
+	// Without the raw payload, e.g. restored from a database, and altered
+	message.RawPayload = nil
+	message.Payload.Readings[0].Time = "yesterday"
+	report = message.Report(&s.privateKey.PublicKey)
+	s.Equal(VerdictInvalid, report.Verdict)
+	s.Contains(report.Warnings, "the payload was re-encoded, the signed bytes are not known")
+	s.Contains(report.Warnings, `RD[0].TM cannot be parsed: "yesterday"`)
+	s.Nil(report.Readings[0].Timestamp)
+	s.Require().Len(report.ValidationErrors, 1)
+	s.Equal("RD[0].TM", report.ValidationErrors[0].Field)
+}
+
+func (s *reportTestSuite) TestReportContext() {
+	message := s.buildMessage(Reading{
+		Time:         "2018-07-24T13:22:04,000+0200 S",
+		ReadingValue: 1,
+		ReadingUnit:  string(UnitskWh),
+		Status:       string(MeterOk),
+	})
+
+	registry := NewKeyRegistry()
+	registry.Register("exampleSerial123", &s.privateKey.PublicKey)
+
+	report := message.ReportContext(context.Background(), registry)
+	s.Equal(VerdictValid, report.Verdict)
+	s.Equal(KeySourceResolver, report.KeySource)
+
+	report = message.ReportContext(context.Background(), nil)
+	s.Equal(VerdictError, report.Verdict)
+
+	registry.Remove("exampleSerial123")
+	report = message.ReportContext(context.Background(), registry)
+	s.Equal(VerdictError, report.Verdict)
+	s.Contains(report.Error, ErrKeyNotFound.Error())
+	s.Empty(report.KeyFingerprint)
+}
+
+func (s *reportTestSuite) TestParser_GetReport() {
+	message := s.buildMessage(Reading{
+		Time:         "2018-07-24T13:22:04,000+0200 S",
+		ReadingValue: 1,
+		ReadingUnit:  string(UnitskWh),
+		Status:       string(MeterOk),
+	})
+
+	report, err := NewParser(WithAutomaticSignatureVerification(&s.privateKey.PublicKey)).
+		ParseOcmfMessageFromString(message.String()).
+		GetReport(context.Background())
+	s.Require().NoError(err)
+	s.Equal(VerdictValid, report.Verdict)
+	s.Equal(KeySourceProvided, report.KeySource)
+
+	// A failed verification is part of the report
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	report, err = NewParser(WithAutomaticSignatureVerification(&otherKey.PublicKey)).
+		ParseMessage(*message).
+		GetReport(context.Background())
+	s.Require().NoError(err)
+	s.Equal(VerdictInvalid, report.Verdict)
+
+	_, err = NewParser().ParseOcmfMessageFromString("OCMF|{}").GetReport(context.Background())
+	s.ErrorIs(err, ErrInvalidFormat)
+}
+
+func TestVerificationReport(t *testing.T) {
+	suite.Run(t, new(reportTestSuite))
+}
@@ -0,0 +1,303 @@
+package ocmf_go
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	ErrUnsupportedFormatVersion = errors.New("unsupported format version")
+	ErrUnsupportedSchemaRule    = errors.New("validation rule cannot be expressed in JSON schema")
+)
+
+// SupportedFormatVersions lists the format versions JSON schemas can be generated for.
+var SupportedFormatVersions = []string{OcmfVersion}
+
+type SchemaDefinition string
+
+const (
+	SchemaPayloadSection   = SchemaDefinition("PayloadSection")
+	SchemaReading          = SchemaDefinition("Reading")
+	SchemaLossCompensation = SchemaDefinition("LossCompensation")
+	SchemaSignature        = SchemaDefinition("Signature")
+)
+
+var schemaDefinitions = map[SchemaDefinition]reflect.Type{
+	SchemaPayloadSection:   reflect.TypeOf(PayloadSection{}),
+	SchemaReading:          reflect.TypeOf(Reading{}),
+	SchemaLossCompensation: reflect.TypeOf(LossCompensation{}),
+	SchemaSignature:        reflect.TypeOf(Signature{}),
+}
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) needed to describe the OCMF sections.
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        any                    `json:"type,omitempty"`
+	Const       any                    `json:"const,omitempty"`
+	Default     any                    `json:"default,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty"`
+	MinLength   *int                   `json:"minLength,omitempty"`
+	MaxLength   *int                   `json:"maxLength,omitempty"`
+	MaxItems    *int                   `json:"maxItems,omitempty"`
+	Not         *JSONSchema            `json:"not,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Defs        map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// schemaDefaults holds the values assumed for omitted fields of a section, see Signature.WithDefaults.
+var schemaDefaults = map[reflect.Type]any{
+	reflect.TypeOf(Signature{}): *Signature{}.WithDefaults(),
+}
+
+// schemaPatterns holds the regular expressions of the pattern rules, without anchors.
+var schemaPatterns = map[string]string{
+	"pagination": unanchored(indicatorNumberRegex.String()),
+	"iso8601":    unanchored(iso8601WithMillisRegex.String()),
+	// Same expression as the hexadecimal rule of the validator
+	"hexadecimal": `(0[xX])?[0-9a-fA-F]+`,
+}
+
+func unanchored(pattern string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+}
+
+// enumRuleValues lists the values accepted by the enumeration rules.
+var enumRuleValues = map[string][]string{
+	"meterError":            meterErrorDescriptions.values(),
+	"userAssignmentState":   userAssignmentStateDescriptions.values(),
+	"ocppState":             ocppStateDescriptions.values(),
+	"rfidState":             rfidStateDescriptions.values(),
+	"timeStatus":            timeStatusDescriptions.values(),
+	"transactionType":       transactionTypeDescriptions.values(),
+	"iso5118State":          iso15118StateDescriptions.values(),
+	"chargePointAssignment": chargePointAssignmentTypeDescriptions.values(),
+	"unit":                  unitsDescriptions.values(),
+	"currentType":           currentTypeDescriptions.values(),
+	"signatureAlgorithm": {
+		string(SignatureAlgorithmECDSAsecp192k1SHA256),
+		string(SignatureAlgorithmECDSAsecp256k1SHA256),
+		string(SignatureAlgorithmECDSAsecp384r1SHA256),
+		string(SignatureAlgorithmECDSAbrainpool256r11SHA256),
+		string(SignatureAlgorithmECDSAsecp256r1SHA256),
+		string(SignatureAlgorithmECDSAsecp192r1SHA256),
+	},
+	"signatureEncoding": {string(SignatureEncodingBase64), string(SignatureEncodingHex)},
+}
+
+// GenerateJSONSchema derives the JSON schema of a section from the validation rules of its struct.
+// Nested sections are included as definitions, so every schema is a standalone document.
+func GenerateJSONSchema(formatVersion string, definition SchemaDefinition) (*JSONSchema, error) {
+	if !slices.Contains(SupportedFormatVersions, formatVersion) {
+		return nil, errors.Wrapf(ErrUnsupportedFormatVersion, "%q", formatVersion)
+	}
+
+	structType, found := schemaDefinitions[definition]
+	if !found {
+		return nil, errors.Errorf("unknown schema definition: %s", definition)
+	}
+
+	defs := map[string]*JSONSchema{}
+	schema, err := structSchema(structType, defs)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Schema = jsonSchemaDialect
+	schema.Title = "OCMF " + formatVersion + " " + string(definition)
+	if len(defs) > 0 {
+		schema.Defs = defs
+	}
+
+	// Payloads of this schema must declare the format version, if they declare one at all
+	if versionSchema, found := schema.Properties["FV"]; found {
+		versionSchema.Const = formatVersion
+	}
+
+	return schema, nil
+}
+
+func structSchema(structType reflect.Type, defs map[string]*JSONSchema) (*JSONSchema, error) {
+	schema := &JSONSchema{
+		Type:       "object",
+		Properties: map[string]*JSONSchema{},
+	}
+
+	defaults, hasDefaults := schemaDefaults[structType]
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := jsonFieldName(field)
+		tag := field.Tag.Get("validate")
+
+		var defaultValue any
+		if hasDefaults {
+			if value := reflect.ValueOf(defaults).Field(i); !value.IsZero() {
+				defaultValue = value.Interface()
+				// Omitted and empty fields are replaced by the default, so they are optional
+				tag = optional(tag)
+			}
+		}
+
+		fieldSchema, required, err := fieldSchema(field.Type, tag, defs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s.%s", structType.Name(), name)
+		}
+		fieldSchema.Default = defaultValue
+
+		schema.Properties[name] = fieldSchema
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema, nil
+}
+
+// fieldSchema converts the type and validation rules of a field. Rules after "dive" apply to the slice elements.
+func fieldSchema(fieldType reflect.Type, tag string, defs map[string]*JSONSchema) (*JSONSchema, bool, error) {
+	rules, elementRules, _ := strings.Cut(tag, ",dive")
+	elementRules = strings.TrimPrefix(elementRules, ",")
+
+	schema, err := typeSchema(fieldType, defs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if fieldType.Kind() == reflect.Slice {
+		items, _, err := fieldSchema(fieldType.Elem(), elementRules, defs)
+		if err != nil {
+			return nil, false, err
+		}
+		schema.Items = items
+	}
+
+	required, err := applyRules(schema, fieldType.Kind(), rules)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Nil slices are encoded as null and accepted unless the field is required
+	if fieldType.Kind() == reflect.Slice && !required {
+		schema.Type = []string{"array", "null"}
+	}
+
+	return schema, required, nil
+}
+
+func typeSchema(fieldType reflect.Type, defs map[string]*JSONSchema) (*JSONSchema, error) {
+	switch fieldType.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}, nil
+	case reflect.Slice:
+		return &JSONSchema{Type: "array"}, nil
+	case reflect.Struct:
+		if _, found := defs[fieldType.Name()]; !found {
+			// Reserve the name first, in case the struct refers to itself
+			defs[fieldType.Name()] = nil
+			definition, err := structSchema(fieldType, defs)
+			if err != nil {
+				return nil, err
+			}
+			defs[fieldType.Name()] = definition
+		}
+
+		return &JSONSchema{Ref: "#/$defs/" + fieldType.Name()}, nil
+	default:
+		return nil, errors.Errorf("unsupported field type: %s", fieldType)
+	}
+}
+
+// applyRules adds the constraints of the validation rules to the schema and reports whether the field is required.
+func applyRules(schema *JSONSchema, kind reflect.Kind, tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+
+	rules := strings.Split(tag, ",")
+	// Empty values skip all other rules
+	omitEmpty := slices.Contains(rules, "omitempty")
+	required := false
+
+	for _, rule := range rules {
+		rule, param, _ := strings.Cut(rule, "=")
+
+		switch {
+		case rule == "omitempty":
+		case rule == "required":
+			required = true
+			switch kind {
+			case reflect.String:
+				schema.MinLength = intPtr(1)
+			case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int64:
+				schema.Not = &JSONSchema{Const: 0}
+			case reflect.Bool:
+				schema.Const = true
+			}
+		case rule == "max":
+			limit, err := strconv.Atoi(param)
+			if err != nil {
+				return false, errors.Wrapf(ErrUnsupportedSchemaRule, "%s=%s", rule, param)
+			}
+
+			switch kind {
+			case reflect.String:
+				schema.MaxLength = intPtr(limit)
+			case reflect.Slice:
+				schema.MaxItems = intPtr(limit)
+			default:
+				return false, errors.Wrapf(ErrUnsupportedSchemaRule, "%s on %s", rule, kind)
+			}
+		case rule == "oneof":
+			schema.Enum = strings.Fields(param)
+		case schemaPatterns[rule] != "":
+			schema.Pattern = "^(?:" + schemaPatterns[rule] + ")$"
+			if omitEmpty {
+				schema.Pattern = "^(?:" + schemaPatterns[rule] + ")?$"
+			}
+		case enumRuleValues[rule] != nil:
+			schema.Enum = slices.Clone(enumRuleValues[rule])
+			if enum, isDescribed := enumRules[rule]; isDescribed {
+				schema.Description = enum.list(LanguageEnglish)
+			}
+		default:
+			return false, errors.Wrapf(ErrUnsupportedSchemaRule, "%s", rule)
+		}
+	}
+
+	if omitEmpty && schema.Enum != nil {
+		schema.Enum = append(schema.Enum, "")
+	}
+
+	return required, nil
+}
+
+// optional removes the required rule, and lets empty values skip the other rules.
+func optional(tag string) string {
+	rules := slices.DeleteFunc(strings.Split(tag, ","), func(rule string) bool {
+		return rule == "required" || rule == "omitempty"
+	})
+
+	return strings.Join(append([]string{"omitempty"}, rules...), ",")
+}
+
+func intPtr(value int) *int {
+	return &value
+}
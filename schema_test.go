@@ -0,0 +1,262 @@
+package ocmf_go
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type schemaTestSuite struct {
+	suite.Suite
+}
+
+func (s *schemaTestSuite) compile(definition SchemaDefinition) *jsonschema.Schema {
+	schema, err := GenerateJSONSchema(OcmfVersion, definition)
+	s.Require().NoError(err)
+
+	data, err := json.Marshal(schema)
+	s.Require().NoError(err)
+
+	compiler := jsonschema.NewCompiler()
+	s.Require().NoError(compiler.AddResource("schema.json", bytes.NewReader(data)))
+
+	compiled, err := compiler.Compile("schema.json")
+	s.Require().NoError(err)
+	return compiled
+}
+
+func (s *schemaTestSuite) TestGenerateJSONSchema() {
+	schema, err := GenerateJSONSchema(OcmfVersion, SchemaPayloadSection)
+	s.Require().NoError(err)
+
+	s.Equal(jsonSchemaDialect, schema.Schema)
+	s.Equal([]string{"PG", "MS", "IT", "RD"}, schema.Required)
+	s.Equal(OcmfVersion, schema.Properties["FV"].Const)
+	s.Equal("#/$defs/Reading", schema.Properties["RD"].Items.Ref)
+	s.Equal("#/$defs/LossCompensation", schema.Properties["LC"].Ref)
+	s.Contains(schema.Defs, "Reading")
+	s.Contains(schema.Defs, "LossCompensation")
+
+	status := schema.Defs["Reading"].Properties["ST"]
+	s.Contains(status.Enum, string(MeterManipulated))
+	s.NotContains(status.Enum, "")
+	s.Contains(status.Description, "M (Manipulated)")
+	s.Contains(schema.Defs["Reading"].Properties["TX"].Enum, "")
+
+	signature, err := GenerateJSONSchema(OcmfVersion, SchemaSignature)
+	s.Require().NoError(err)
+	s.Equal([]string{"SD"}, signature.Required)
+	s.Empty(signature.Defs)
+	s.EqualValues(SignatureAlgorithmECDSAsecp256r1SHA256, signature.Properties["SA"].Default)
+	s.EqualValues(SignatureEncodingHex, signature.Properties["SE"].Default)
+	s.EqualValues(SignatureMimeTypeDer, signature.Properties["SM"].Default)
+	s.Nil(signature.Properties["SD"].Default)
+
+	_, err = GenerateJSONSchema("0.1", SchemaPayloadSection)
+	s.ErrorIs(err, ErrUnsupportedFormatVersion)
+
+	_, err = GenerateJSONSchema(OcmfVersion, "Message")
+	s.Error(err)
+}
+
+// validPayload returns a payload section as it is decoded from JSON, so it can be modified per test case.
+func validPayload() map[string]any {
+	payload := PayloadSection{
+		FormatVersion:      OcmfVersion,
+		Pagination:         "T1",
+		MeterSerial:        "exampleSerial123",
+		IdentificationType: string(RfidNone),
+		Readings: []Reading{
+			{
+				Time:         "2018-07-24T13:22:04,000+0200 S",
+				Transaction:  string(TransactionBegin),
+				ReadingValue: 1,
+				ReadingUnit:  string(UnitskWh),
+				Status:       string(MeterOk),
+			},
+		},
+	}
+
+	data, _ := json.Marshal(payload)
+	decoded := map[string]any{}
+	_ = json.Unmarshal(data, &decoded)
+	return decoded
+}
+
+func reading(payload map[string]any) map[string]any {
+	return payload["RD"].([]any)[0].(map[string]any)
+}
+
+// TestSchemaMatchesValidation checks that the generated schemas accept exactly the documents the Go validation accepts.
+func (s *schemaTestSuite) TestSchemaMatchesValidation() {
+	payloadSchema := s.compile(SchemaPayloadSection)
+
+	tests := []struct {
+		name   string
+		modify func(payload map[string]any)
+		valid  bool
+	}{
+		{name: "Valid", modify: func(map[string]any) {}, valid: true},
+		{name: "Missing pagination", modify: func(p map[string]any) { delete(p, "PG") }},
+		{name: "Invalid pagination", modify: func(p map[string]any) { p["PG"] = "1" }},
+		{name: "Fiscal pagination", modify: func(p map[string]any) { p["PG"] = "F12" }, valid: true},
+		{name: "Empty meter serial", modify: func(p map[string]any) { p["MS"] = "" }},
+		{name: "Missing meter serial", modify: func(p map[string]any) { delete(p, "MS") }},
+		{name: "Valid identification level", modify: func(p map[string]any) { p["IL"] = "TRUSTED" }, valid: true},
+		{name: "Invalid identification level", modify: func(p map[string]any) { p["IL"] = "X" }},
+		{name: "Empty identification level", modify: func(p map[string]any) { p["IL"] = "" }, valid: true},
+		{name: "Four identification flags", modify: func(p map[string]any) { p["IF"] = []any{"a", "b", "c", "d"} }, valid: true},
+		{name: "Too many identification flags", modify: func(p map[string]any) { p["IF"] = []any{"a", "b", "c", "d", "e"} }},
+		{name: "Missing identification type", modify: func(p map[string]any) { p["IT"] = "" }},
+		{name: "Unsupported identification type", modify: func(p map[string]any) { p["IT"] = string(OcppRemoteStart) }},
+		{name: "Hexadecimal identification data", modify: func(p map[string]any) { p["ID"] = "0x1F2D3A4B" }, valid: true},
+		{name: "Invalid identification data", modify: func(p map[string]any) { p["ID"] = "XYZ" }},
+		{name: "Tariff text too long", modify: func(p map[string]any) { p["TT"] = strings.Repeat("a", 251) }},
+		{name: "Charge controller version too long", modify: func(p map[string]any) { p["CF"] = strings.Repeat("a", 26) }},
+		{name: "Valid charge point type", modify: func(p map[string]any) { p["CT"] = "EVSE_ID" }, valid: true},
+		{name: "Invalid charge point type", modify: func(p map[string]any) { p["CT"] = "FOO" }},
+		{name: "Missing readings", modify: func(p map[string]any) { delete(p, "RD") }},
+		{name: "Empty readings", modify: func(p map[string]any) { p["RD"] = []any{} }, valid: true},
+		{name: "Invalid reading time", modify: func(p map[string]any) { reading(p)["TM"] = "2018-07-24T13:22:04Z" }},
+		{name: "Relative reading time", modify: func(p map[string]any) { reading(p)["TM"] = "2018-07-24T13:22:04,000+0200 R" }, valid: true},
+		{name: "Invalid transaction", modify: func(p map[string]any) { reading(p)["TX"] = "Q" }},
+		{name: "End transaction", modify: func(p map[string]any) { reading(p)["TX"] = "E" }, valid: true},
+		{name: "Zero reading value", modify: func(p map[string]any) { reading(p)["RV"] = 0 }},
+		{name: "Invalid unit", modify: func(p map[string]any) { reading(p)["RU"] = "MWh" }},
+		{name: "Valid current type", modify: func(p map[string]any) { reading(p)["RT"] = "DC" }, valid: true},
+		{name: "Invalid current type", modify: func(p map[string]any) { reading(p)["RT"] = "XX" }},
+		{name: "Valid error flags", modify: func(p map[string]any) { reading(p)["EF"] = "t" }, valid: true},
+		{name: "Invalid error flags", modify: func(p map[string]any) { reading(p)["EF"] = "x" }},
+		{name: "Invalid meter status", modify: func(p map[string]any) { reading(p)["ST"] = "Z" }},
+		{name: "Other format version", modify: func(p map[string]any) { p["FV"] = "1.0" }},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			document := validPayload()
+			tt.modify(document)
+
+			data, err := json.Marshal(document)
+			s.Require().NoError(err)
+
+			payload := PayloadSection{}
+			s.Require().NoError(json.Unmarshal(data, &payload))
+			goErr := payload.Validate()
+
+			schemaErr := payloadSchema.Validate(document)
+			s.Equal(tt.valid, schemaErr == nil, "schema: %v", schemaErr)
+
+			// The format version is only enforced by the versioned schema
+			if document["FV"] == OcmfVersion {
+				s.Equal(tt.valid, goErr == nil, "validation: %v", goErr)
+			}
+		})
+	}
+}
+
+func (s *schemaTestSuite) TestSignatureSchemaMatchesValidation() {
+	signatureSchema := s.compile(SchemaSignature)
+
+	data, err := json.Marshal(validPayload())
+	s.Require().NoError(err)
+	payload := PayloadSection{}
+	s.Require().NoError(json.Unmarshal(data, &payload))
+
+	tests := []struct {
+		name   string
+		modify func(signature map[string]any)
+		valid  bool
+	}{
+		{name: "Valid", modify: func(map[string]any) {}, valid: true},
+		{name: "Invalid algorithm", modify: func(sig map[string]any) { sig["SA"] = "RSA-SHA256" }},
+		{name: "Other algorithm", modify: func(sig map[string]any) { sig["SA"] = string(SignatureAlgorithmECDSAsecp384r1SHA256) }, valid: true},
+		{name: "Invalid encoding", modify: func(sig map[string]any) { sig["SE"] = "base32" }},
+		{name: "Missing encoding", modify: func(sig map[string]any) { delete(sig, "SE") }, valid: true},
+		{name: "Empty encoding", modify: func(sig map[string]any) { sig["SE"] = "" }, valid: true},
+		{name: "Invalid mime type", modify: func(sig map[string]any) { sig["SM"] = "text/plain" }},
+		{name: "Only data", modify: func(sig map[string]any) {
+			delete(sig, "SA")
+			delete(sig, "SE")
+			delete(sig, "SM")
+		}, valid: true},
+		{name: "Missing data", modify: func(sig map[string]any) { sig["SD"] = "" }},
+		{name: "Omitted data", modify: func(sig map[string]any) { delete(sig, "SD") }},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			document := map[string]any{
+				"SA": string(SignatureAlgorithmECDSAsecp256r1SHA256),
+				"SE": string(SignatureEncodingHex),
+				"SM": string(SignatureMimeTypeDer),
+				"SD": "3045",
+			}
+			tt.modify(document)
+
+			data, err := json.Marshal(document)
+			s.Require().NoError(err)
+
+			signature := Signature{}
+			s.Require().NoError(json.Unmarshal(data, &signature))
+
+			// Messages are validated with the defaults of the omitted fields
+			message := Message{Payload: payload, Signature: signature}
+			messageErr := message.Validate()
+			s.Equal(tt.valid, messageErr == nil, "validation: %v", messageErr)
+
+			schemaErr := signatureSchema.Validate(document)
+			s.Equal(tt.valid, schemaErr == nil, "schema: %v", schemaErr)
+		})
+	}
+}
+
+func (s *schemaTestSuite) TestStandaloneSchemas() {
+	readingSchema := s.compile(SchemaReading)
+	document := reading(validPayload())
+	s.NoError(readingSchema.Validate(document))
+
+	document["ST"] = "Z"
+	s.Error(readingSchema.Validate(document))
+
+	lossCompensationSchema := s.compile(SchemaLossCompensation)
+	s.NoError(lossCompensationSchema.Validate(map[string]any{"LN": "cable", "LI": 1, "LR": 1.5, "LU": "mOhm"}))
+	s.Error(lossCompensationSchema.Validate(map[string]any{"LI": "one"}))
+}
+
+// TestEnumRuleValues checks that every listed value is accepted by the validator of its rule.
+func (s *schemaTestSuite) TestEnumRuleValues() {
+	validators := map[string]func(string) bool{
+		"meterError":            func(v string) bool { return isValidMeterError(MeterError(v)) },
+		"userAssignmentState":   func(v string) bool { return isValidUserAssignmentState(UserAssignmentState(v)) },
+		"ocppState":             func(v string) bool { return isValidOcppState(OcppState(v)) },
+		"rfidState":             func(v string) bool { return isValidRfidState(RfidState(v)) },
+		"timeStatus":            func(v string) bool { return isValidTimeStatus(TimeStatus(v)) },
+		"transactionType":       func(v string) bool { return isValidTransactionType(TransactionType(v)) },
+		"iso5118State":          func(v string) bool { return isValidISO15118State(ISO15118State(v)) },
+		"chargePointAssignment": func(v string) bool { return isValidChargePointAssignmentType(ChargePointAssignmentType(v)) },
+		"unit":                  func(v string) bool { return isValidUnit(Units(v)) },
+		"currentType":           func(v string) bool { return isValidCurrentType(CurrentType(v)) },
+		"signatureAlgorithm":    func(v string) bool { return isValidSignatureAlgorithm(SignatureAlgorithm(v)) },
+		"signatureEncoding":     func(v string) bool { return isValidSignatureEncoding(SignatureEncoding(v)) },
+	}
+
+	s.Len(enumRuleValues, len(validators))
+	for rule, values := range enumRuleValues {
+		s.T().Run(rule, func(t *testing.T) {
+			isValid, found := validators[rule]
+			s.Require().True(found)
+
+			for _, value := range values {
+				s.True(isValid(value), value)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	suite.Run(t, new(schemaTestSuite))
+}
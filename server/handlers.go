@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+type messageRequest struct {
+	Message string `json:"message"`
+	// PublicKey is an optional key sent along with the message. If the server has a key registry,
+	// the key must match the registered key of the meter. Otherwise, it is only used if the server trusts request keys.
+	PublicKey      string `json:"publicKey,omitempty"`
+	SkipValidation bool   `json:"skipValidation,omitempty"`
+}
+
+type sessionRequest struct {
+	Messages       []string `json:"messages"`
+	PublicKey      string   `json:"publicKey,omitempty"`
+	SkipValidation bool     `json:"skipValidation,omitempty"`
+}
+
+type signatureDetails struct {
+	Algorithm ocmf.SignatureAlgorithm `json:"algorithm,omitempty"`
+	Encoding  ocmf.SignatureEncoding  `json:"encoding,omitempty"`
+	MimeType  ocmf.SignatureMimeType  `json:"mimeType,omitempty"`
+	Data      string                  `json:"data"`
+}
+
+type messageResponse struct {
+	Valid       bool                 `json:"valid"`
+	Payload     *ocmf.PayloadSection `json:"payload,omitempty"`
+	Signature   *signatureDetails    `json:"signature,omitempty"`
+	Error       string               `json:"error,omitempty"`
+	Diagnostics []ocmf.FieldError    `json:"diagnostics,omitempty"`
+}
+
+type sessionResponse struct {
+	Valid    bool                `json:"valid"`
+	Messages []messageResponse   `json:"messages"`
+	Events   []ocmf.TrackerEvent `json:"events,omitempty"`
+	Errors   []string            `json:"errors,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	var request messageRequest
+	if !s.decodeRequest(w, r, &request) {
+		return
+	}
+
+	message, err := ocmf.ParseMessage(request.Message)
+	if err != nil {
+		writeJSON(w, http.StatusOK, messageResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newMessageResponse(message, true))
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var request messageRequest
+	if !s.decodeRequest(w, r, &request) {
+		return
+	}
+
+	message, err := ocmf.ParseMessage(request.Message)
+	if err != nil {
+		writeJSON(w, http.StatusOK, messageResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, validateMessage(message))
+}
+
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	var request messageRequest
+	if !s.decodeRequest(w, r, &request) {
+		return
+	}
+
+	embeddedKey, err := parseRequestKey(request.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.verifyMessage(r.Context(), request.Message, embeddedKey, !request.SkipValidation))
+}
+
+func (s *Server) handleVerifySession(w http.ResponseWriter, r *http.Request) {
+	var request sessionRequest
+	if !s.decodeRequest(w, r, &request) {
+		return
+	}
+
+	if len(request.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("messages are required"))
+		return
+	}
+
+	embeddedKey, err := parseRequestKey(request.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	response := sessionResponse{
+		Valid:    true,
+		Messages: []messageResponse{},
+	}
+
+	tracker := ocmf.NewTracker()
+	var payloads []ocmf.PayloadSection
+	for i, data := range request.Messages {
+		result := s.verifyMessage(r.Context(), data, embeddedKey, !request.SkipValidation)
+		response.Messages = append(response.Messages, result)
+		if !result.Valid {
+			response.Valid = false
+			continue
+		}
+
+		payloads = append(payloads, *result.Payload)
+
+		events, err := tracker.Ingest(*result.Payload)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("message %d: %v", i, err))
+			continue
+		}
+		response.Events = append(response.Events, events...)
+	}
+
+	response.Errors = append(response.Errors, checkSessionBoundaries(payloads)...)
+	if len(response.Events) > 0 || len(response.Errors) > 0 {
+		response.Valid = false
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// checkSessionBoundaries requires a session to start with a transaction begin and end with a transaction end.
+func checkSessionBoundaries(payloads []ocmf.PayloadSection) []string {
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	var problems []string
+	first, last := payloads[0], payloads[len(payloads)-1]
+	if len(first.Readings) == 0 || ocmf.TransactionType(first.Readings[0].Transaction) != ocmf.TransactionBegin {
+		problems = append(problems, "session does not start with a transaction begin reading")
+	}
+
+	if len(last.Readings) == 0 || !ocmf.TransactionType(last.Readings[len(last.Readings)-1].Transaction).IsEnd() {
+		problems = append(problems, "session does not end with a transaction end reading")
+	}
+
+	return problems
+}
+
+func (s *Server) verifyMessage(ctx context.Context, data string, embeddedKey *ecdsa.PublicKey, withValidation bool) messageResponse {
+	message, err := ocmf.ParseMessage(data)
+	if err != nil {
+		return messageResponse{Error: err.Error()}
+	}
+
+	if withValidation {
+		response := validateMessage(message)
+		if !response.Valid {
+			return response
+		}
+	}
+
+	response := newMessageResponse(message, false)
+
+	if s.resolver == nil && embeddedKey != nil && !s.trustRequestKeys {
+		response.Error = ErrUntrustedPublicKey.Error()
+		return response
+	}
+
+	publicKey, err := ocmf.SelectPublicKey(ctx, s.resolver, message.Payload.MeterSerial, embeddedKey)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+
+	valid, err := message.Verify(publicKey)
+	switch {
+	case err != nil:
+		response.Error = errors.Wrap(err, "failed to verify signature").Error()
+	case !valid:
+		response.Error = "invalid signature"
+	default:
+		response.Valid = true
+	}
+
+	return response
+}
+
+func validateMessage(message *ocmf.Message) messageResponse {
+	response := newMessageResponse(message, false)
+
+	diagnostics := append(ocmf.FieldErrors(message.Payload.Validate()), ocmf.FieldErrors(message.Signature.WithDefaults().Validate())...)
+	if len(diagnostics) > 0 {
+		response.Error = "validation failed"
+		response.Diagnostics = diagnostics
+		return response
+	}
+
+	response.Valid = true
+	return response
+}
+
+func newMessageResponse(message *ocmf.Message, valid bool) messageResponse {
+	signature := message.Signature.WithDefaults()
+	return messageResponse{
+		Valid:   valid,
+		Payload: &message.Payload,
+		Signature: &signatureDetails{
+			Algorithm: signature.Algorithm,
+			Encoding:  signature.Encoding,
+			MimeType:  signature.MimeType,
+			Data:      message.Signature.Data,
+		},
+	}
+}
+
+func parseRequestKey(key string) (*ecdsa.PublicKey, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	return ocmf.ParsePublicKey([]byte(key))
+}
+
+// decodeRequest decodes the JSON body into the request, writing an error response if it fails.
+func (s *Server) decodeRequest(w http.ResponseWriter, r *http.Request, request any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestSize)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeError(w, http.StatusRequestEntityTooLarge, errors.New("request body too large"))
+			return false
+		}
+
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request body"))
+		return false
+	}
+
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
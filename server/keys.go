@@ -0,0 +1,41 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadKeyRegistry reads a JSON or YAML document mapping meter serials to public keys
+// in any encoding supported by ocmf.ParsePublicKey.
+func LoadKeyRegistry(r io.Reader) (*ocmf.KeyRegistry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read keys")
+	}
+
+	keys := map[string]string{}
+	if json.Valid(data) {
+		err = json.Unmarshal(data, &keys)
+	} else {
+		err = yaml.Unmarshal(data, &keys)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode keys")
+	}
+
+	registry := ocmf.NewKeyRegistry()
+	for meterSerial, key := range keys {
+		publicKey, err := ocmf.ParsePublicKey([]byte(key))
+		if err != nil {
+			return nil, errors.Wrapf(err, "meter %s", meterSerial)
+		}
+
+		registry.Register(meterSerial, publicKey)
+	}
+
+	return registry, nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type keysTestSuite struct {
+	suite.Suite
+}
+
+func (s *keysTestSuite) TestLoadKeyRegistry() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	der, err := ocmf.MarshalPublicKey(&privateKey.PublicKey)
+	s.Require().NoError(err)
+	key := hex.EncodeToString(der)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "JSON", data: fmt.Sprintf(`{"meter1": %q}`, key)},
+		{name: "YAML", data: fmt.Sprintf("meter1: %s\n", key)},
+		{name: "Invalid key", data: `{"meter1": "abc"}`, wantErr: true},
+		{name: "Invalid document", data: "- a\n- b\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			registry, err := LoadKeyRegistry(strings.NewReader(tt.data))
+			if tt.wantErr {
+				s.Error(err)
+				return
+			}
+
+			s.Require().NoError(err)
+			publicKey, err := registry.ResolvePublicKey(context.Background(), "meter1")
+			s.Require().NoError(err)
+			s.True(publicKey.Equal(&privateKey.PublicKey))
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	suite.Run(t, new(keysTestSuite))
+}
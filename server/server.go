@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+// ErrUntrustedPublicKey is returned for public keys sent with a request if the server has no key registry to check
+// them against and WithTrustRequestKeys is not set.
+var ErrUntrustedPublicKey = errors.New("public keys sent with the request are not trusted")
+
+const (
+	DefaultMaxRequestSize = 1024 * 1024
+	DefaultRequestTimeout = 10 * time.Second
+)
+
+type Option func(*Server)
+
+// WithMaxRequestSize limits the size of request bodies in bytes.
+func WithMaxRequestSize(size int64) Option {
+	return func(s *Server) {
+		if size > 0 {
+			s.maxRequestSize = size
+		}
+	}
+}
+
+// WithRequestTimeout limits the time spent on a single request, including key lookups.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		if timeout > 0 {
+			s.requestTimeout = timeout
+		}
+	}
+}
+
+// WithTrustRequestKeys verifies messages with the public key sent in the request if the server has no key registry.
+// A message verified this way was signed with the sent key, which proves nothing about the meter that signed it.
+func WithTrustRequestKeys() Option {
+	return func(s *Server) {
+		s.trustRequestKeys = true
+	}
+}
+
+// Server exposes parsing, validation and verification of OCMF messages over a JSON API.
+type Server struct {
+	resolver       ocmf.KeyResolver
+	maxRequestSize int64
+	requestTimeout time.Duration
+	// trustRequestKeys allows the keys sent in requests to be used without a resolver.
+	trustRequestKeys bool
+}
+
+// New creates a Server. The resolver provides the trusted public keys; it may be nil,
+// in which case messages can only be verified with keys sent in the request if WithTrustRequestKeys is set.
+func New(resolver ocmf.KeyResolver, opts ...Option) *Server {
+	server := &Server{
+		resolver:       resolver,
+		maxRequestSize: DefaultMaxRequestSize,
+		requestTimeout: DefaultRequestTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(server)
+	}
+
+	return server
+}
+
+// Handler returns the HTTP handler with all API routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("POST /v1/parse", s.handleParse)
+	mux.HandleFunc("POST /v1/validate", s.handleValidate)
+	mux.HandleFunc("POST /v1/verify", s.handleVerify)
+	mux.HandleFunc("POST /v1/sessions/verify", s.handleVerifySession)
+
+	return http.TimeoutHandler(mux, s.requestTimeout, `{"error":"request timed out"}`)
+}
+
+// ListenAndServe serves the API on the address until the context is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       s.requestTimeout,
+		// Leave room for the timeout handler to write its response
+		WriteTimeout: s.requestTimeout + 5*time.Second,
+		IdleTimeout:  time.Minute,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return errors.Wrap(err, "server failed")
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type serverTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	registry   *ocmf.KeyRegistry
+	handler    http.Handler
+	messages   []string
+}
+
+func (s *serverTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.registry = ocmf.NewKeyRegistry()
+	s.registry.Register("exampleSerial123", &privateKey.PublicKey)
+	s.handler = New(s.registry, WithMaxRequestSize(4096)).Handler()
+
+	template := ocmf.NewBuilder(privateKey).WithMeterSerial("exampleSerial123").Template()
+	s.messages = []string{}
+
+	for _, reading := range []ocmf.Reading{
+		{Time: "2018-07-24T13:22:04,000+0200 S", Transaction: "B", ReadingValue: 10},
+		{Time: "2018-07-24T13:24:04,000+0200 S", Transaction: "C", ReadingValue: 11},
+		{Time: "2018-07-24T13:26:04,000+0200 S", Transaction: "E", ReadingValue: 12},
+	} {
+		reading.ReadingUnit = string(ocmf.UnitskWh)
+		reading.Status = string(ocmf.MeterOk)
+
+		message, err := template.NewBuilder().
+			WithPagination(fmt.Sprintf("T%d", len(s.messages)+1)).
+			WithIdentificationType(string(ocmf.RfidNone)).
+			AddReading(reading).
+			Build()
+		s.Require().NoError(err)
+
+		s.messages = append(s.messages, message.String())
+	}
+}
+
+func (s *serverTestSuite) post(path string, body any) (*httptest.ResponseRecorder, map[string]any) {
+	data, err := json.Marshal(body)
+	s.Require().NoError(err)
+
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+
+	response := map[string]any{}
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+	return recorder, response
+}
+
+func (s *serverTestSuite) TestHealth() {
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	s.Equal(http.StatusOK, recorder.Code)
+	s.JSONEq(`{"status":"ok"}`, recorder.Body.String())
+}
+
+func (s *serverTestSuite) TestParse() {
+	recorder, response := s.post("/v1/parse", messageRequest{Message: s.messages[0]})
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(true, response["valid"])
+	s.Equal("exampleSerial123", response["payload"].(map[string]any)["MS"])
+	s.Equal(string(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256), response["signature"].(map[string]any)["algorithm"])
+
+	recorder, response = s.post("/v1/parse", messageRequest{Message: "OCMF|{}"})
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(false, response["valid"])
+	s.NotEmpty(response["error"])
+}
+
+func (s *serverTestSuite) TestValidate() {
+	recorder, response := s.post("/v1/validate", messageRequest{Message: s.messages[0]})
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(true, response["valid"])
+
+	recorder, response = s.post("/v1/validate", messageRequest{Message: `OCMF|{"FV":"1.0","PG":"X1"}|{"SD":"00"}`})
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(false, response["valid"])
+	s.Equal("validation failed", response["error"])
+	s.NotEmpty(response["diagnostics"])
+}
+
+func (s *serverTestSuite) TestVerify() {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	otherDer, err := ocmf.MarshalPublicKey(&otherKey.PublicKey)
+	s.Require().NoError(err)
+
+	ownDer, err := ocmf.MarshalPublicKey(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+
+	tampered := strings.Replace(s.messages[0], `"RV":10`, `"RV":100`, 1)
+	s.Require().NotEqual(s.messages[0], tampered)
+
+	tests := []struct {
+		name          string
+		request       messageRequest
+		expectedValid bool
+		expectedError string
+	}{
+		{
+			name:          "Registered key",
+			request:       messageRequest{Message: s.messages[0]},
+			expectedValid: true,
+		},
+		{
+			name:          "Matching key in request",
+			request:       messageRequest{Message: s.messages[0], PublicKey: hex.EncodeToString(ownDer)},
+			expectedValid: true,
+		},
+		{
+			name:          "Mismatching key in request",
+			request:       messageRequest{Message: s.messages[0], PublicKey: hex.EncodeToString(otherDer)},
+			expectedError: ocmf.ErrPublicKeyMismatch.Error(),
+		},
+		{
+			name:          "Tampered payload",
+			request:       messageRequest{Message: tampered},
+			expectedError: "invalid signature",
+		},
+		{
+			name:          "Malformed message",
+			request:       messageRequest{Message: "not a message"},
+			expectedError: ocmf.ErrInvalidFormat.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			recorder, response := s.post("/v1/verify", tt.request)
+			s.Equal(http.StatusOK, recorder.Code)
+			s.Equal(tt.expectedValid, response["valid"])
+
+			if tt.expectedError != "" {
+				s.Contains(response["error"], tt.expectedError)
+			}
+		})
+	}
+}
+
+func (s *serverTestSuite) TestVerifyUnknownMeter() {
+	s.registry.Remove("exampleSerial123")
+
+	_, response := s.post("/v1/verify", messageRequest{Message: s.messages[0]})
+	s.Equal(false, response["valid"])
+	s.Contains(response["error"], ocmf.ErrKeyNotFound.Error())
+}
+
+func (s *serverTestSuite) TestVerifyWithoutRegistry() {
+	ownDer, err := ocmf.MarshalPublicKey(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		request       messageRequest
+		expectedValid bool
+		expectedError string
+	}{
+		{
+			name:          "Key in request",
+			request:       messageRequest{Message: s.messages[0], PublicKey: hex.EncodeToString(ownDer)},
+			expectedError: ErrUntrustedPublicKey.Error(),
+		},
+		{
+			name:          "Trusted key in request",
+			opts:          []Option{WithTrustRequestKeys()},
+			request:       messageRequest{Message: s.messages[0], PublicKey: hex.EncodeToString(ownDer)},
+			expectedValid: true,
+		},
+		{
+			name:          "No key",
+			opts:          []Option{WithTrustRequestKeys()},
+			request:       messageRequest{Message: s.messages[0]},
+			expectedError: ocmf.ErrPublicKeyMissing.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.handler = New(nil, tt.opts...).Handler()
+
+			recorder, response := s.post("/v1/verify", tt.request)
+			s.Equal(http.StatusOK, recorder.Code)
+			s.Equal(tt.expectedValid, response["valid"])
+
+			if tt.expectedError != "" {
+				s.Contains(response["error"], tt.expectedError)
+			}
+		})
+	}
+
+	// Sessions are checked the same way
+	s.handler = New(nil, WithTrustRequestKeys()).Handler()
+	_, response := s.post("/v1/sessions/verify", sessionRequest{Messages: s.messages, PublicKey: hex.EncodeToString(ownDer)})
+	s.Equal(true, response["valid"])
+
+	s.handler = New(nil).Handler()
+	_, response = s.post("/v1/sessions/verify", sessionRequest{Messages: s.messages, PublicKey: hex.EncodeToString(ownDer)})
+	s.Equal(false, response["valid"])
+}
+
+func (s *serverTestSuite) TestVerifySession() {
+	recorder, response := s.post("/v1/sessions/verify", sessionRequest{Messages: s.messages})
+	s.Equal(http.StatusOK, recorder.Code)
+	s.Equal(true, response["valid"])
+	s.Len(response["messages"], 3)
+	s.Nil(response["events"])
+
+	// Missing intermediate message and end
+	_, response = s.post("/v1/sessions/verify", sessionRequest{Messages: []string{s.messages[0], s.messages[2]}})
+	s.Equal(false, response["valid"])
+	s.Len(response["events"], 1)
+	s.Equal(string(ocmf.TrackerEventGap), response["events"].([]any)[0].(map[string]any)["type"])
+
+	_, response = s.post("/v1/sessions/verify", sessionRequest{Messages: s.messages[:2]})
+	s.Equal(false, response["valid"])
+	s.Equal([]any{"session does not end with a transaction end reading"}, response["errors"])
+}
+
+func (s *serverTestSuite) TestBadRequests() {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Invalid JSON", path: "/v1/verify", body: `{`, expectedStatus: http.StatusBadRequest},
+		{name: "Unknown field", path: "/v1/verify", body: `{"msg":"OCMF"}`, expectedStatus: http.StatusBadRequest},
+		{name: "Invalid public key", path: "/v1/verify", body: `{"message":"OCMF","publicKey":"abc"}`, expectedStatus: http.StatusBadRequest},
+		{name: "No session messages", path: "/v1/sessions/verify", body: `{"messages":[]}`, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Body too large",
+			path:           "/v1/verify",
+			body:           `{"message":"` + strings.Repeat("a", 8192) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			s.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+			s.Equal(tt.expectedStatus, recorder.Code)
+			s.Contains(recorder.Body.String(), `"error"`)
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/verify", nil))
+	s.Equal(http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
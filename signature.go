@@ -1,8 +1,9 @@
 package ocmf_go
 
 import (
+	"context"
 	"crypto/ecdsa"
-	"crypto/rand"
+	"crypto/elliptic"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
@@ -59,8 +60,39 @@ func isValidSignatureAlgorithm(algorithm SignatureAlgorithm) bool {
 	}
 }
 
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+	ErrKeyAlgorithmMismatch = errors.New("key does not match the signature algorithm")
+)
+
+// curveForAlgorithm returns the curve of the algorithm. Only curves available in crypto/elliptic can be used for signing and verification.
+func curveForAlgorithm(algorithm SignatureAlgorithm) (elliptic.Curve, error) {
+	switch algorithm {
+	case SignatureAlgorithmECDSAsecp256r1SHA256:
+		return elliptic.P256(), nil
+	case SignatureAlgorithmECDSAsecp384r1SHA256:
+		return elliptic.P384(), nil
+	default:
+		return nil, errors.Wrapf(ErrUnsupportedAlgorithm, "%s", algorithm)
+	}
+}
+
+// checkKeyAlgorithm checks that the key is on the curve required by the algorithm.
+func checkKeyAlgorithm(publicKey *ecdsa.PublicKey, algorithm SignatureAlgorithm) error {
+	curve, err := curveForAlgorithm(algorithm)
+	if err != nil {
+		return err
+	}
+
+	if publicKey.Curve == nil || publicKey.Curve.Params().Name != curve.Params().Name {
+		return errors.Wrapf(ErrKeyAlgorithmMismatch, "%s requires curve %s", algorithm, curve.Params().Name)
+	}
+
+	return nil
+}
+
 type Signature struct {
-	Algorithm SignatureAlgorithm `json:"SA" validate:"required,signatureAlgorithm"`
+	Algorithm SignatureAlgorithm `json:"SA,omitempty" validate:"required,signatureAlgorithm"`
 	Encoding  SignatureEncoding  `json:"SE,omitempty" validate:"required,signatureEncoding"`
 	MimeType  SignatureMimeType  `json:"SM,omitempty" validate:"required,oneof=application/x-der"`
 	Data      string             `json:"SD" validate:"required"`
@@ -74,36 +106,79 @@ func NewDefaultSignature() *Signature {
 	}
 }
 
+// WithDefaults returns a copy of the signature with the values the OCMF specification assumes for omitted
+// SA, SE and SM fields. Parsed signatures are kept as received, so they can be marshalled to the same section.
+func (s Signature) WithDefaults() *Signature {
+	if s.Algorithm == "" {
+		s.Algorithm = SignatureAlgorithmECDSAsecp256r1SHA256
+	}
+
+	if s.Encoding == "" {
+		s.Encoding = SignatureEncodingHex
+	}
+
+	if s.MimeType == "" {
+		s.MimeType = SignatureMimeTypeDer
+	}
+
+	return &s
+}
+
 func (s *Signature) Validate() error {
 	return signatureValidator.Struct(s)
 }
 
 func (s *Signature) Sign(payload PayloadSection, privateKey *ecdsa.PrivateKey) error {
-	if privateKey == nil {
-		return errors.New("private key is required")
+	signer, err := NewPrivateKeySigner(privateKey)
+	if err != nil {
+		return err
 	}
 
+	return s.SignContext(context.Background(), payload, signer)
+}
+
+// SignContext signs the payload with the signer. The context is passed on to the signer.
+func (s *Signature) SignContext(ctx context.Context, payload PayloadSection, signer Signer) error {
 	// Marshal payload
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal payload")
 	}
 
-	switch s.Algorithm {
-	case SignatureAlgorithmECDSAsecp192k1SHA256:
-	case SignatureAlgorithmECDSAsecp256k1SHA256:
-	case SignatureAlgorithmECDSAsecp384r1SHA256:
-	case SignatureAlgorithmECDSAbrainpool256r11SHA256:
-	case SignatureAlgorithmECDSAsecp256r1SHA256:
-	default:
-		return fmt.Errorf("unsupported signature algorithm: %s", s.Algorithm)
+	return s.SignBytesContext(ctx, payloadBytes, signer)
+}
+
+// SignBytes signs the already encoded payload section.
+func (s *Signature) SignBytes(payloadBytes []byte, privateKey *ecdsa.PrivateKey) error {
+	signer, err := NewPrivateKeySigner(privateKey)
+	if err != nil {
+		return err
+	}
+
+	return s.SignBytesContext(context.Background(), payloadBytes, signer)
+}
+
+// SignBytesContext signs the already encoded payload section with the signer. The context is passed on to the signer.
+func (s *Signature) SignBytesContext(ctx context.Context, payloadBytes []byte, signer Signer) error {
+	if signer == nil {
+		return errors.New("signer is required")
+	}
+
+	publicKey := signer.Public()
+	if publicKey == nil {
+		return errors.New("signer has no public key")
+	}
+
+	err := checkKeyAlgorithm(publicKey, s.Algorithm)
+	if err != nil {
+		return err
 	}
 
 	// Hash data
 	messageHash := sha256.Sum256(payloadBytes)
 
 	// Sign data
-	sign, err := ecdsa.SignASN1(rand.Reader, privateKey, messageHash[:])
+	sign, err := signer.SignDigest(ctx, messageHash[:])
 	if err != nil {
 		return errors.Wrap(err, "failed to sign data")
 	}
@@ -125,45 +200,51 @@ func (s *Signature) Sign(payload PayloadSection, privateKey *ecdsa.PrivateKey) e
 }
 
 func (s *Signature) Verify(payload PayloadSection, publicKey *ecdsa.PublicKey) (bool, error) {
+	if publicKey == nil {
+		return false, errors.New("public key is required")
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	return s.VerifyBytes(payloadBytes, publicKey)
+}
+
+// VerifyBytes verifies the signature against the payload section exactly as it was signed.
+func (s *Signature) VerifyBytes(payloadBytes []byte, publicKey *ecdsa.PublicKey) (bool, error) {
 	var decoded []byte
 
 	if publicKey == nil {
 		return false, errors.New("public key is required")
 	}
 
+	signature := s.WithDefaults()
+
 	// Decode the signature
-	switch s.Encoding {
+	switch signature.Encoding {
 	case SignatureEncodingBase64:
-		decodedString, err := base64.StdEncoding.DecodeString(s.Data)
+		decodedString, err := base64.StdEncoding.DecodeString(signature.Data)
 		if err != nil {
 			return false, errors.Wrap(err, "failed to decode base64 data")
 		}
 
 		decoded = decodedString
 	case SignatureEncodingHex:
-		decodedString, err := hex.DecodeString(s.Data)
+		decodedString, err := hex.DecodeString(signature.Data)
 		if err != nil {
 			return false, errors.Wrap(err, "failed to decode hex data")
 		}
 
 		decoded = decodedString
 	default:
-		return false, fmt.Errorf("unsupported signature encoding: %s", s.Encoding)
-	}
-
-	switch s.Algorithm {
-	case SignatureAlgorithmECDSAsecp192k1SHA256:
-	case SignatureAlgorithmECDSAsecp256k1SHA256:
-	case SignatureAlgorithmECDSAsecp384r1SHA256:
-	case SignatureAlgorithmECDSAbrainpool256r11SHA256:
-	case SignatureAlgorithmECDSAsecp256r1SHA256:
-	default:
-		return false, fmt.Errorf("unsupported signature algorithm: %s", s.Algorithm)
+		return false, fmt.Errorf("unsupported signature encoding: %s", signature.Encoding)
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	err := checkKeyAlgorithm(publicKey, signature.Algorithm)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to marshal payload")
+		return false, err
 	}
 
 	// Hash the payload to compare with the signature
@@ -102,10 +102,80 @@ func TestIsValidSignatureAlgorithm(t *testing.T) {
 	}
 }
 
+func TestCheckKeyAlgorithm(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		key       *ecdsa.PublicKey
+		algorithm SignatureAlgorithm
+		error     error
+	}{
+		{
+			name:      "P256 with secp256r1",
+			key:       &p256Key.PublicKey,
+			algorithm: SignatureAlgorithmECDSAsecp256r1SHA256,
+		},
+		{
+			name:      "P384 with secp384r1",
+			key:       &p384Key.PublicKey,
+			algorithm: SignatureAlgorithmECDSAsecp384r1SHA256,
+		},
+		{
+			name:      "P256 with secp384r1",
+			key:       &p256Key.PublicKey,
+			algorithm: SignatureAlgorithmECDSAsecp384r1SHA256,
+			error:     ErrKeyAlgorithmMismatch,
+		},
+		{
+			name:      "Unsupported curve",
+			key:       &p256Key.PublicKey,
+			algorithm: SignatureAlgorithmECDSAbrainpool256r11SHA256,
+			error:     ErrUnsupportedAlgorithm,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkKeyAlgorithm(test.key, test.algorithm)
+			if test.error != nil {
+				assert.ErrorIs(t, err, test.error)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 type signatureTestSuite struct {
 	suite.Suite
 }
 
+func (s *signatureTestSuite) TestWithDefaults() {
+	signature := Signature{Data: "3045"}
+
+	withDefaults := signature.WithDefaults()
+	s.Equal(&Signature{
+		Algorithm: SignatureAlgorithmECDSAsecp256r1SHA256,
+		Encoding:  SignatureEncodingHex,
+		MimeType:  SignatureMimeTypeDer,
+		Data:      "3045",
+	}, withDefaults)
+	s.NoError(withDefaults.Validate())
+
+	// The signature itself is not changed
+	s.Equal(Signature{Data: "3045"}, signature)
+	s.Error(signature.Validate())
+
+	// Transmitted values are kept
+	signature = Signature{Algorithm: SignatureAlgorithmECDSAsecp384r1SHA256, Encoding: SignatureEncodingBase64, Data: "MEU="}
+	s.Equal(SignatureAlgorithmECDSAsecp384r1SHA256, signature.WithDefaults().Algorithm)
+	s.Equal(SignatureEncodingBase64, signature.WithDefaults().Encoding)
+}
+
 func (s *signatureTestSuite) TestValidate() {
 	tests := []struct {
 		name      string
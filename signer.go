@@ -0,0 +1,43 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+
+	"github.com/pkg/errors"
+)
+
+// Signer creates the signatures of OCMF messages, e.g. with a key held by an HSM or a remote signing service.
+type Signer interface {
+	// Public returns the public key of the signing key.
+	Public() *ecdsa.PublicKey
+	// SignDigest signs the SHA256 digest of the payload and returns the ASN.1 DER encoded signature.
+	// Implementations must return once the context is done.
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+type privateKeySigner struct {
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewPrivateKeySigner returns a Signer for a key held in memory.
+func NewPrivateKeySigner(privateKey *ecdsa.PrivateKey) (Signer, error) {
+	if privateKey == nil {
+		return nil, errors.New("private key is required")
+	}
+
+	return &privateKeySigner{privateKey: privateKey}, nil
+}
+
+func (s *privateKeySigner) Public() *ecdsa.PublicKey {
+	return &s.privateKey.PublicKey
+}
+
+func (s *privateKeySigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return ecdsa.SignASN1(rand.Reader, s.privateKey, digest)
+}
@@ -0,0 +1,195 @@
+package ocmf_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// slowSigner stands in for a remote signing service that takes a while to respond.
+type slowSigner struct {
+	Signer
+	delay time.Duration
+}
+
+func (s *slowSigner) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(s.delay):
+	}
+
+	return s.Signer.SignDigest(ctx, digest)
+}
+
+type signerTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+}
+
+func (s *signerTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+}
+
+func (s *signerTestSuite) slowSigner(delay time.Duration) Signer {
+	signer, err := NewPrivateKeySigner(s.privateKey)
+	s.Require().NoError(err)
+	return &slowSigner{Signer: signer, delay: delay}
+}
+
+func (s *signerTestSuite) buildMessage(ctx context.Context, builder *Builder) (*Message, error) {
+	return builder.
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(RfidNone)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 1,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		BuildContext(ctx)
+}
+
+func (s *signerTestSuite) TestNewPrivateKeySigner() {
+	_, err := NewPrivateKeySigner(nil)
+	s.Error(err)
+
+	signer, err := NewPrivateKeySigner(s.privateKey)
+	s.Require().NoError(err)
+	s.True(s.privateKey.PublicKey.Equal(signer.Public()))
+
+	digest := sha256.Sum256([]byte("payload"))
+	signature, err := signer.SignDigest(context.Background(), digest[:])
+	s.Require().NoError(err)
+	s.True(ecdsa.VerifyASN1(&s.privateKey.PublicKey, digest[:], signature))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = signer.SignDigest(ctx, digest[:])
+	s.ErrorIs(err, context.Canceled)
+}
+
+func (s *signerTestSuite) TestSignBytesContext() {
+	payload := []byte(`{"FV":"1.0","PG":"T1"}`)
+
+	signature := NewDefaultSignature()
+	s.Require().NoError(signature.SignBytesContext(context.Background(), payload, s.slowSigner(time.Millisecond)))
+
+	valid, err := signature.VerifyBytes(payload, &s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	signature = NewDefaultSignature()
+	err = signature.SignBytesContext(ctx, payload, s.slowSigner(time.Minute))
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Empty(signature.Data)
+
+	err = signature.SignBytesContext(context.Background(), payload, nil)
+	s.ErrorContains(err, "signer is required")
+
+	signature.Algorithm = SignatureAlgorithmECDSAsecp384r1SHA256
+	err = signature.SignBytesContext(context.Background(), payload, s.slowSigner(0))
+	s.ErrorIs(err, ErrKeyAlgorithmMismatch)
+}
+
+func (s *signerTestSuite) TestBuildContext() {
+	// The signer is shared with the builders of the template
+	template := NewBuilderWithSigner(s.slowSigner(time.Minute)).Template()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := s.buildMessage(ctx, template.NewBuilder())
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Less(time.Since(start), time.Minute)
+
+	message, err := s.buildMessage(context.Background(), NewBuilderWithSigner(s.slowSigner(time.Millisecond)))
+	s.Require().NoError(err)
+
+	valid, err := message.Verify(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	_, err = NewBuilderE(nil, WithSignatureAlgorithm(SignatureAlgorithmECDSAsecp384r1SHA256))
+	s.ErrorContains(err, "signer is required")
+
+	builder := NewBuilderWithSigner(s.slowSigner(0), WithSignatureAlgorithm(SignatureAlgorithmECDSAsecp384r1SHA256))
+	s.ErrorIs(builder.Err(), ErrKeyAlgorithmMismatch)
+}
+
+func (s *signerTestSuite) TestParserContext() {
+	message, err := s.buildMessage(context.Background(), NewBuilder(s.privateKey))
+	s.Require().NoError(err)
+
+	registry := NewKeyRegistry()
+	registry.Register("exampleSerial123", &s.privateKey.PublicKey)
+
+	// A key lookup that only returns once the context is done
+	slowResolver := KeyResolverFunc(func(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = NewParser(WithKeyResolver(slowResolver)).ParseMessage(*message).GetMessageContext(ctx)
+	s.ErrorIs(err, context.DeadlineExceeded)
+
+	parsed, err := NewParser(WithKeyResolver(registry)).ParseOcmfMessageFromString(message.String()).GetMessageContext(context.Background())
+	s.Require().NoError(err)
+	s.Equal(message.Payload, parsed.Payload)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	_, err = NewParser(WithKeyResolver(registry), WithAutomaticSignatureVerification(&otherKey.PublicKey)).
+		ParseMessage(*message).
+		GetSignature()
+	s.ErrorIs(err, ErrPublicKeyMismatch)
+
+	registry.Register("exampleSerial123", &otherKey.PublicKey)
+	_, err = NewParser(WithKeyResolver(registry)).ParseMessage(*message).GetSignature()
+	s.ErrorIs(err, ErrVerificationFailure)
+}
+
+func (s *signerTestSuite) TestVerifyContext() {
+	message, err := s.buildMessage(context.Background(), NewBuilder(s.privateKey))
+	s.Require().NoError(err)
+
+	registry := NewKeyRegistry()
+	registry.Register("exampleSerial123", &s.privateKey.PublicKey)
+
+	valid, err := message.VerifyContext(context.Background(), registry)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = message.VerifyContext(ctx, registry)
+	s.ErrorIs(err, context.Canceled)
+
+	_, err = message.VerifyContext(context.Background(), nil)
+	s.Error(err)
+
+	registry.Remove("exampleSerial123")
+	_, err = message.VerifyContext(context.Background(), registry)
+	s.ErrorIs(err, ErrKeyNotFound)
+}
+
+func TestSigner(t *testing.T) {
+	suite.Run(t, new(signerTestSuite))
+}
@@ -0,0 +1,61 @@
+package simulator
+
+import (
+	"strconv"
+	"strings"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidFault = errors.New("invalid fault")
+
+type FaultKind string
+
+const (
+	// FaultTimeout reports a timeout status (T) in the readings of one message.
+	FaultTimeout = FaultKind(ocmf.MeterTimeout)
+	// FaultDisconnected reports a disconnected status (D) in the readings of one message.
+	FaultDisconnected = FaultKind(ocmf.MeterDisconnected)
+	// FaultManipulated reports the manipulated status (M). Like a real meter, it keeps reporting it afterwards.
+	FaultManipulated = FaultKind(ocmf.MeterManipulated)
+	// FaultTimeReset resets the meter clock to Config.ResetTime. The clock is reported as unknown (U)
+	// with the time error flag set until it is synchronized again.
+	FaultTimeReset = FaultKind("reset")
+)
+
+// Fault is injected before the meter builds the message with the given number.
+// Messages are numbered from 1 over the lifetime of the meter, like the transaction pagination counter.
+type Fault struct {
+	Kind    FaultKind
+	Message int
+}
+
+// ParseFault parses a fault in the <kind>@<message> form used by the command-line tool, e.g. "T@3" or "reset@5".
+func ParseFault(description string) (Fault, error) {
+	kind, message, found := strings.Cut(description, "@")
+	if !found {
+		return Fault{}, errors.Wrapf(ErrInvalidFault, "%q", description)
+	}
+
+	number, err := strconv.Atoi(message)
+	if err != nil || number < 1 {
+		return Fault{}, errors.Wrapf(ErrInvalidFault, "%q: invalid message number", description)
+	}
+
+	fault := Fault{Kind: FaultKind(kind), Message: number}
+	if !isValidFaultKind(fault.Kind) {
+		return Fault{}, errors.Wrapf(ErrInvalidFault, "%q: unknown kind", description)
+	}
+
+	return fault, nil
+}
+
+func isValidFaultKind(kind FaultKind) bool {
+	switch kind {
+	case FaultTimeout, FaultDisconnected, FaultManipulated, FaultTimeReset:
+		return true
+	default:
+		return false
+	}
+}
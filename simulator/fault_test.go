@@ -0,0 +1,45 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type faultTestSuite struct {
+	suite.Suite
+}
+
+func (s *faultTestSuite) TestParseFault() {
+	tests := []struct {
+		name        string
+		description string
+		expected    Fault
+		wantErr     bool
+	}{
+		{name: "Timeout", description: "T@3", expected: Fault{Kind: FaultTimeout, Message: 3}},
+		{name: "Disconnected", description: "D@1", expected: Fault{Kind: FaultDisconnected, Message: 1}},
+		{name: "Manipulated", description: "M@2", expected: Fault{Kind: FaultManipulated, Message: 2}},
+		{name: "Time reset", description: "reset@5", expected: Fault{Kind: FaultTimeReset, Message: 5}},
+		{name: "Missing message", description: "T", wantErr: true},
+		{name: "Invalid message", description: "T@0", wantErr: true},
+		{name: "Unknown kind", description: "G@1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			fault, err := ParseFault(tt.description)
+			if tt.wantErr {
+				s.ErrorIs(err, ErrInvalidFault)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Equal(tt.expected, fault)
+		})
+	}
+}
+
+func TestFault(t *testing.T) {
+	suite.Run(t, new(faultTestSuite))
+}
@@ -0,0 +1,370 @@
+package simulator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math"
+	"sync"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrTransactionActive = errors.New("transaction already active")
+	ErrNoTransaction     = errors.New("no active transaction")
+)
+
+// DefaultReadingIdentifier is the OBIS code of the imported active energy register.
+const DefaultReadingIdentifier = "1-b:1.8.0"
+
+type Config struct {
+	MeterSerial   string
+	MeterVendor   string
+	MeterModel    string
+	MeterFirmware string
+	PrivateKey    *ecdsa.PrivateKey
+	// Algorithm defaults to the algorithm matching the curve of the private key.
+	Algorithm ocmf.SignatureAlgorithm
+	// PaginationManager issues the transaction counters. Defaults to an in-memory manager.
+	PaginationManager *ocmf.PaginationManager
+
+	IdentificationType string
+	IdentificationData string
+
+	// Register is the initial register value in Wh. Readings must not be zero, so it defaults to 1 kWh.
+	Register    float64
+	CurrentType ocmf.CurrentType
+	Profile     PowerProfile
+	// StartTime is the initial meter time. Defaults to the current time.
+	StartTime time.Time
+	// ResetTime is the time the clock is set to by a FaultTimeReset. Defaults to 2000-01-01 UTC.
+	ResetTime time.Time
+	// Interval is the meter time between the intermediate messages of Run.
+	Interval time.Duration
+	// Pace is the real time Run waits between messages. Zero produces the messages as fast as possible.
+	Pace   time.Duration
+	Faults []Fault
+}
+
+// Meter is a virtual meter producing signed messages. It is safe for concurrent use.
+type Meter struct {
+	mu       sync.Mutex
+	config   Config
+	template *ocmf.Template
+
+	register   float64
+	clock      time.Time
+	timeStatus ocmf.TimeStatus
+	timeError  bool
+	// status is the sticky status of the meter, e.g. after a manipulation was detected
+	status ocmf.MeterError
+
+	active   bool
+	elapsed  time.Duration
+	messages int
+	faults   map[int][]FaultKind
+}
+
+func NewMeter(config Config) (*Meter, error) {
+	if config.MeterSerial == "" {
+		return nil, errors.New("meter serial is required")
+	}
+
+	if config.PrivateKey == nil {
+		return nil, errors.New("private key is required")
+	}
+
+	for _, fault := range config.Faults {
+		if !isValidFaultKind(fault.Kind) {
+			return nil, errors.Wrapf(ErrInvalidFault, "unknown kind %q", fault.Kind)
+		}
+
+		if fault.Message < 1 {
+			return nil, errors.Wrapf(ErrInvalidFault, "%s: invalid message number %d", fault.Kind, fault.Message)
+		}
+	}
+
+	if config.Algorithm == "" {
+		config.Algorithm = ocmf.SignatureAlgorithmECDSAsecp256r1SHA256
+		if config.PrivateKey.Curve.Params().Name == "P-384" {
+			config.Algorithm = ocmf.SignatureAlgorithmECDSAsecp384r1SHA256
+		}
+	}
+
+	if config.PaginationManager == nil {
+		config.PaginationManager = ocmf.NewPaginationManager(nil)
+	}
+
+	if config.IdentificationType == "" {
+		config.IdentificationType = string(ocmf.RfidNone)
+	}
+
+	if config.Register <= 0 {
+		config.Register = 1000
+	}
+
+	if config.CurrentType == "" {
+		config.CurrentType = ocmf.CurrentTypeAC
+	}
+
+	if config.Profile == nil {
+		config.Profile = ConstantPower(11000)
+	}
+
+	if config.StartTime.IsZero() {
+		config.StartTime = time.Now()
+	}
+
+	if config.ResetTime.IsZero() {
+		config.ResetTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+
+	builder, err := ocmf.NewBuilderE(config.PrivateKey,
+		ocmf.WithSignatureAlgorithm(config.Algorithm),
+		ocmf.WithPaginationManager(config.PaginationManager, ocmf.PaginationTransaction),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid meter configuration")
+	}
+
+	meter := &Meter{
+		config: config,
+		template: builder.
+			WithMeterSerial(config.MeterSerial).
+			WithMeterVendor(config.MeterVendor).
+			WithMeterModel(config.MeterModel).
+			WithMeterFirmware(config.MeterFirmware).
+			Template(),
+		register:   config.Register,
+		clock:      config.StartTime.Truncate(time.Millisecond),
+		timeStatus: ocmf.TimeStatusSynchronized,
+		status:     ocmf.MeterOk,
+		faults:     map[int][]FaultKind{},
+	}
+
+	for _, fault := range config.Faults {
+		meter.schedule(fault)
+	}
+
+	return meter, nil
+}
+
+// Inject schedules a fault for the next message.
+func (m *Meter) Inject(kind FaultKind) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !isValidFaultKind(kind) {
+		return errors.Wrapf(ErrInvalidFault, "unknown kind %q", kind)
+	}
+
+	m.schedule(Fault{Kind: kind, Message: m.messages + 1})
+	return nil
+}
+
+func (m *Meter) schedule(fault Fault) {
+	m.faults[fault.Message] = append(m.faults[fault.Message], fault.Kind)
+}
+
+// SyncClock sets the meter clock, e.g. after a time reset, and marks it as synchronized.
+func (m *Meter) SyncClock(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = now.Truncate(time.Millisecond)
+	m.timeStatus = ocmf.TimeStatusSynchronized
+	m.timeError = false
+}
+
+// Now returns the meter time.
+func (m *Meter) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.clock
+}
+
+// Register returns the register value in Wh.
+func (m *Meter) Register() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.register
+}
+
+func (m *Meter) PublicKey() *ecdsa.PublicKey {
+	return &m.config.PrivateKey.PublicKey
+}
+
+// Idle advances the meter time outside of a transaction.
+func (m *Meter) Idle(duration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active {
+		return ErrTransactionActive
+	}
+
+	m.clock = m.clock.Add(duration)
+	return nil
+}
+
+// Begin starts a transaction and returns the transaction begin message.
+func (m *Meter) Begin() (*ocmf.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active {
+		return nil, ErrTransactionActive
+	}
+
+	message, err := m.build(ocmf.TransactionBegin)
+	if err != nil {
+		return nil, err
+	}
+
+	m.active = true
+	m.elapsed = 0
+	return message, nil
+}
+
+// Advance charges for the duration according to the power profile and returns an intermediate message.
+func (m *Meter) Advance(duration time.Duration) (*ocmf.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.active {
+		return nil, ErrNoTransaction
+	}
+
+	m.charge(duration)
+	return m.build(ocmf.TransactionCharging)
+}
+
+// End charges for the duration and returns the transaction end message.
+func (m *Meter) End(duration time.Duration) (*ocmf.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.active {
+		return nil, ErrNoTransaction
+	}
+
+	m.charge(duration)
+	message, err := m.build(ocmf.TransactionEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	m.active = false
+	return message, nil
+}
+
+// Run simulates a transaction with the given number of intermediate messages, one every Config.Interval,
+// and passes every message to emit as soon as it is built.
+func (m *Meter) Run(ctx context.Context, intermediates int, emit func(ocmf.Message) error) error {
+	message, err := m.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := emit(*message); err != nil {
+		return err
+	}
+
+	for i := 0; i <= intermediates; i++ {
+		if err := m.wait(ctx); err != nil {
+			return err
+		}
+
+		if i == intermediates {
+			message, err = m.End(m.config.Interval)
+		} else {
+			message, err = m.Advance(m.config.Interval)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := emit(*message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Meter) wait(ctx context.Context) error {
+	if m.config.Pace <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(m.config.Pace)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (m *Meter) charge(duration time.Duration) {
+	m.register += energy(m.config.Profile, m.elapsed, m.elapsed+duration)
+	m.elapsed += duration
+	m.clock = m.clock.Add(duration)
+}
+
+// build applies the faults scheduled for the next message and builds it. The caller must hold the lock.
+func (m *Meter) build(transaction ocmf.TransactionType) (*ocmf.Message, error) {
+	number := m.messages + 1
+
+	status := m.status
+	for _, kind := range m.faults[number] {
+		switch kind {
+		case FaultTimeReset:
+			m.clock = m.config.ResetTime
+			m.timeStatus = ocmf.TimeStatusUnknown
+			m.timeError = true
+		case FaultManipulated:
+			m.status = ocmf.MeterManipulated
+			status = m.status
+		default:
+			status = ocmf.MeterError(kind)
+		}
+	}
+	delete(m.faults, number)
+
+	reading := ocmf.Reading{
+		Time:              m.clock.Format(ocmf.ReadingTimeLayout) + " " + string(m.timeStatus),
+		Transaction:       string(transaction),
+		ReadingValue:      math.Round(m.register) / 1000,
+		ReadingIdentifier: DefaultReadingIdentifier,
+		ReadingUnit:       string(ocmf.UnitskWh),
+		ReadingType:       string(m.config.CurrentType),
+		Status:            string(status),
+	}
+	if m.timeError {
+		reading.ErrorFlags = "t"
+	}
+
+	message, err := m.template.NewBuilder().
+		WithIdentificationStatus(m.config.IdentificationData != "").
+		WithIdentificationType(m.config.IdentificationType).
+		WithIdentificationData(m.config.IdentificationData).
+		AddReading(reading).
+		Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build message")
+	}
+
+	m.messages = number
+	return message, nil
+}
@@ -0,0 +1,234 @@
+package simulator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type meterTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	start      time.Time
+}
+
+func (s *meterTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+	s.start = time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+}
+
+func (s *meterTestSuite) newMeter(config Config) *Meter {
+	config.MeterSerial = "SIM-0001"
+	config.PrivateKey = s.privateKey
+	config.StartTime = s.start
+
+	meter, err := NewMeter(config)
+	s.Require().NoError(err)
+	return meter
+}
+
+// verify parses, validates and verifies the message like a receiving backend would.
+func (s *meterTestSuite) verify(message ocmf.Message) ocmf.Reading {
+	parsed, err := ocmf.NewParser(
+		ocmf.WithAutomaticValidation(),
+		ocmf.WithAutomaticSignatureVerification(&s.privateKey.PublicKey),
+	).ParseOcmfMessageFromString(message.String()).GetMessage()
+	s.Require().NoError(err)
+	s.Require().Len(parsed.Payload.Readings, 1)
+
+	return parsed.Payload.Readings[0]
+}
+
+func (s *meterTestSuite) TestRun() {
+	meter := s.newMeter(Config{Profile: ConstantPower(6000), Interval: 10 * time.Minute})
+
+	messages := []ocmf.Message{}
+	err := meter.Run(context.Background(), 2, func(message ocmf.Message) error {
+		messages = append(messages, message)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Len(messages, 4)
+
+	expected := []struct {
+		pagination  string
+		transaction ocmf.TransactionType
+		value       float64
+		time        string
+	}{
+		{pagination: "T1", transaction: ocmf.TransactionBegin, value: 1, time: "2024-05-01T10:00:00,000+0200 S"},
+		{pagination: "T2", transaction: ocmf.TransactionCharging, value: 2, time: "2024-05-01T10:10:00,000+0200 S"},
+		{pagination: "T3", transaction: ocmf.TransactionCharging, value: 3, time: "2024-05-01T10:20:00,000+0200 S"},
+		{pagination: "T4", transaction: ocmf.TransactionEnd, value: 4, time: "2024-05-01T10:30:00,000+0200 S"},
+	}
+
+	tracker := ocmf.NewTracker()
+	for i, message := range messages {
+		reading := s.verify(message)
+		s.Equal(expected[i].pagination, message.Payload.Pagination)
+		s.Equal(string(expected[i].transaction), reading.Transaction)
+		s.InDelta(expected[i].value, reading.ReadingValue, 0.0001)
+		s.Equal(expected[i].time, reading.Time)
+		s.Equal(string(ocmf.MeterOk), reading.Status)
+		s.Equal(DefaultReadingIdentifier, reading.ReadingIdentifier)
+
+		events, err := tracker.Ingest(message.Payload)
+		s.Require().NoError(err)
+		s.Empty(events)
+	}
+
+	s.InDelta(4000.0, meter.Register(), 0.001)
+	s.Equal(s.start.Add(30*time.Minute), meter.Now())
+}
+
+func (s *meterTestSuite) TestTransactionState() {
+	meter := s.newMeter(Config{})
+
+	_, err := meter.Advance(time.Minute)
+	s.ErrorIs(err, ErrNoTransaction)
+
+	_, err = meter.End(time.Minute)
+	s.ErrorIs(err, ErrNoTransaction)
+
+	_, err = meter.Begin()
+	s.Require().NoError(err)
+
+	_, err = meter.Begin()
+	s.ErrorIs(err, ErrTransactionActive)
+	s.ErrorIs(meter.Idle(time.Minute), ErrTransactionActive)
+
+	_, err = meter.End(time.Minute)
+	s.Require().NoError(err)
+	s.NoError(meter.Idle(time.Hour))
+
+	// The next transaction continues the pagination and register
+	message, err := meter.Begin()
+	s.Require().NoError(err)
+	s.Equal("T3", message.Payload.Pagination)
+	s.Equal(s.start.Add(61*time.Minute), meter.Now())
+}
+
+func (s *meterTestSuite) TestStatusFaults() {
+	meter := s.newMeter(Config{
+		Faults: []Fault{
+			{Kind: FaultTimeout, Message: 2},
+			{Kind: FaultManipulated, Message: 4},
+		},
+	})
+
+	statuses := []string{}
+	err := meter.Run(context.Background(), 3, func(message ocmf.Message) error {
+		statuses = append(statuses, s.verify(message).Status)
+		return nil
+	})
+	s.Require().NoError(err)
+
+	s.Equal([]string{"G", "T", "G", "M", "M"}, statuses)
+
+	s.Require().NoError(meter.Inject(FaultDisconnected))
+	message, err := meter.Begin()
+	s.Require().NoError(err)
+	s.Equal("D", s.verify(*message).Status)
+
+	s.ErrorIs(meter.Inject("X"), ErrInvalidFault)
+}
+
+func (s *meterTestSuite) TestTimeReset() {
+	meter := s.newMeter(Config{Faults: []Fault{{Kind: FaultTimeReset, Message: 2}}})
+
+	_, err := meter.Begin()
+	s.Require().NoError(err)
+
+	message, err := meter.Advance(time.Minute)
+	s.Require().NoError(err)
+
+	reading := s.verify(*message)
+	s.Equal("2000-01-01T00:00:00,000+0000 U", reading.Time)
+	s.Equal("t", reading.ErrorFlags)
+
+	// A backend notices the clock going backwards
+	tracker := ocmf.NewTracker()
+	_, err = tracker.Ingest(ocmf.PayloadSection{MeterSerial: "SIM-0001", Pagination: "T1", Readings: []ocmf.Reading{
+		{Time: "2024-05-01T10:00:00,000+0200 S", ReadingUnit: "kWh", ReadingValue: 1},
+	}})
+	s.Require().NoError(err)
+	events, err := tracker.Ingest(message.Payload)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(ocmf.TrackerEventTimeRollback, events[0].Type)
+
+	meter.SyncClock(s.start.Add(time.Hour))
+	message, err = meter.End(time.Minute)
+	s.Require().NoError(err)
+
+	reading = s.verify(*message)
+	s.Equal("2024-05-01T11:01:00,000+0200 S", reading.Time)
+	s.Empty(reading.ErrorFlags)
+}
+
+func (s *meterTestSuite) TestRunCancelled() {
+	meter := s.newMeter(Config{Pace: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	messages := 0
+	done := make(chan error)
+	go func() {
+		done <- meter.Run(ctx, 2, func(ocmf.Message) error {
+			messages++
+			return nil
+		})
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		s.ErrorIs(err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		s.Fail("run was not cancelled")
+	}
+	s.Equal(1, messages)
+
+	emitErr := errors.New("closed")
+	meter = s.newMeter(Config{})
+	s.ErrorIs(meter.Run(context.Background(), 2, func(ocmf.Message) error { return emitErr }), emitErr)
+}
+
+func (s *meterTestSuite) TestNewMeter() {
+	_, err := NewMeter(Config{PrivateKey: s.privateKey})
+	s.Error(err)
+
+	_, err = NewMeter(Config{MeterSerial: "SIM-0001"})
+	s.Error(err)
+
+	_, err = NewMeter(Config{MeterSerial: "SIM-0001", PrivateKey: s.privateKey, Algorithm: ocmf.SignatureAlgorithmECDSAsecp384r1SHA256})
+	s.ErrorIs(err, ocmf.ErrKeyAlgorithmMismatch)
+
+	_, err = NewMeter(Config{MeterSerial: "SIM-0001", PrivateKey: s.privateKey, Faults: []Fault{{Kind: "X", Message: 1}}})
+	s.ErrorIs(err, ErrInvalidFault)
+
+	_, err = NewMeter(Config{MeterSerial: "SIM-0001", PrivateKey: s.privateKey, Faults: []Fault{{Kind: FaultTimeout, Message: 0}}})
+	s.ErrorIs(err, ErrInvalidFault)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	s.Require().NoError(err)
+
+	meter, err := NewMeter(Config{MeterSerial: "SIM-0001", PrivateKey: p384})
+	s.Require().NoError(err)
+
+	message, err := meter.Begin()
+	s.Require().NoError(err)
+	s.Equal(ocmf.SignatureAlgorithmECDSAsecp384r1SHA256, message.Signature.Algorithm)
+}
+
+func TestMeter(t *testing.T) {
+	suite.Run(t, new(meterTestSuite))
+}
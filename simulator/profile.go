@@ -0,0 +1,140 @@
+package simulator
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidProfile = errors.New("invalid power profile")
+
+// PowerProfile returns the charging power in watts at the time elapsed since the transaction began.
+type PowerProfile interface {
+	Power(elapsed time.Duration) float64
+}
+
+type PowerProfileFunc func(elapsed time.Duration) float64
+
+func (f PowerProfileFunc) Power(elapsed time.Duration) float64 {
+	return f(elapsed)
+}
+
+// ConstantPower charges with the same power for the whole transaction.
+type ConstantPower float64
+
+func (p ConstantPower) Power(time.Duration) float64 {
+	return float64(p)
+}
+
+// Ramp changes the power linearly from From to To within Duration and keeps To afterwards.
+type Ramp struct {
+	From     float64
+	To       float64
+	Duration time.Duration
+}
+
+func (r Ramp) Power(elapsed time.Duration) float64 {
+	if r.Duration <= 0 || elapsed >= r.Duration {
+		return r.To
+	}
+
+	return r.From + (r.To-r.From)*float64(elapsed)/float64(r.Duration)
+}
+
+// Taper charges with MaxPower until Start and then halves the power every HalfLife,
+// like a battery switching from constant current to constant voltage charging.
+type Taper struct {
+	MaxPower float64
+	Start    time.Duration
+	HalfLife time.Duration
+}
+
+func (t Taper) Power(elapsed time.Duration) float64 {
+	if elapsed <= t.Start || t.HalfLife <= 0 {
+		return t.MaxPower
+	}
+
+	return t.MaxPower * math.Pow(0.5, float64(elapsed-t.Start)/float64(t.HalfLife))
+}
+
+// ParseProfile parses a profile description as used by the command-line tool:
+//
+//	constant:<watts>
+//	ramp:<from watts>:<to watts>:<duration>
+//	taper:<watts>:<start>:<half life>
+func ParseProfile(description string) (PowerProfile, error) {
+	kind, params, _ := strings.Cut(description, ":")
+	fields := strings.Split(params, ":")
+
+	parseErr := func(err error) error {
+		return errors.Wrapf(ErrInvalidProfile, "%q: %v", description, err)
+	}
+
+	switch {
+	case kind == "constant" && len(fields) == 1:
+		power, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		return ConstantPower(power), nil
+	case kind == "ramp" && len(fields) == 3:
+		from, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		to, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		duration, err := time.ParseDuration(fields[2])
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		return Ramp{From: from, To: to, Duration: duration}, nil
+	case kind == "taper" && len(fields) == 3:
+		power, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		start, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		halfLife, err := time.ParseDuration(fields[2])
+		if err != nil {
+			return nil, parseErr(err)
+		}
+
+		return Taper{MaxPower: power, Start: start, HalfLife: halfLife}, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidProfile, "%q", description)
+	}
+}
+
+// energy integrates the profile between the two elapsed times and returns the energy in Wh.
+func energy(profile PowerProfile, from, to time.Duration) float64 {
+	const steps = 60
+
+	step := (to - from) / steps
+	if step <= 0 {
+		return 0
+	}
+
+	total := 0.0
+	for i := 0; i < steps; i++ {
+		// Midpoint rule
+		power := profile.Power(from + step*time.Duration(i) + step/2)
+		total += math.Max(power, 0) * step.Hours()
+	}
+
+	return total
+}
@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type profileTestSuite struct {
+	suite.Suite
+}
+
+func (s *profileTestSuite) TestProfiles() {
+	s.Equal(11000.0, ConstantPower(11000).Power(time.Hour))
+
+	ramp := Ramp{From: 0, To: 10000, Duration: 10 * time.Minute}
+	s.Equal(0.0, ramp.Power(0))
+	s.Equal(5000.0, ramp.Power(5*time.Minute))
+	s.Equal(10000.0, ramp.Power(time.Hour))
+
+	taper := Taper{MaxPower: 22000, Start: 30 * time.Minute, HalfLife: 10 * time.Minute}
+	s.Equal(22000.0, taper.Power(10*time.Minute))
+	s.InDelta(11000.0, taper.Power(40*time.Minute), 0.001)
+	s.InDelta(5500.0, taper.Power(50*time.Minute), 0.001)
+}
+
+func (s *profileTestSuite) TestEnergy() {
+	s.InDelta(1000.0, energy(ConstantPower(6000), 0, 10*time.Minute), 0.001)
+	s.InDelta(500.0, energy(Ramp{From: 0, To: 6000, Duration: 10 * time.Minute}, 0, 10*time.Minute), 0.001)
+	s.Equal(0.0, energy(ConstantPower(6000), time.Minute, time.Minute))
+	// Negative power does not run the register backwards
+	s.Equal(0.0, energy(ConstantPower(-6000), 0, time.Minute))
+}
+
+func (s *profileTestSuite) TestParseProfile() {
+	tests := []struct {
+		name        string
+		description string
+		expected    PowerProfile
+		wantErr     bool
+	}{
+		{name: "Constant", description: "constant:11000", expected: ConstantPower(11000)},
+		{name: "Ramp", description: "ramp:1000:22000:15m", expected: Ramp{From: 1000, To: 22000, Duration: 15 * time.Minute}},
+		{name: "Taper", description: "taper:50000:20m:10m", expected: Taper{MaxPower: 50000, Start: 20 * time.Minute, HalfLife: 10 * time.Minute}},
+		{name: "Unknown kind", description: "random:100", wantErr: true},
+		{name: "Missing parameters", description: "ramp:1000", wantErr: true},
+		{name: "Invalid power", description: "constant:fast", wantErr: true},
+		{name: "Invalid duration", description: "taper:50000:20:10m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			profile, err := ParseProfile(tt.description)
+			if tt.wantErr {
+				s.ErrorIs(err, ErrInvalidProfile)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Equal(tt.expected, profile)
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+	suite.Run(t, new(profileTestSuite))
+}
@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// migrations are applied in order. The number of applied migrations is kept in the user_version pragma.
+// Never change a released migration, add a new one instead.
+var migrations = []string{
+	// 1: messages, readings, sessions and meter keys
+	`
+CREATE TABLE messages (
+	id                               INTEGER PRIMARY KEY AUTOINCREMENT,
+	raw_payload                      BLOB NOT NULL,
+	raw_signature                    BLOB NOT NULL,
+	format_version                   TEXT NOT NULL,
+	gateway_id                       TEXT NOT NULL,
+	gateway_serial                   TEXT NOT NULL,
+	gateway_version                  TEXT NOT NULL,
+	pagination                       TEXT NOT NULL,
+	meter_vendor                     TEXT NOT NULL,
+	meter_model                      TEXT NOT NULL,
+	meter_serial                     TEXT NOT NULL,
+	meter_firmware                   TEXT NOT NULL,
+	identification_status            INTEGER NOT NULL,
+	identification_level             TEXT NOT NULL,
+	identification_type              TEXT NOT NULL,
+	identification_data              TEXT NOT NULL,
+	tariff_text                      TEXT NOT NULL,
+	charge_point_identification_type TEXT NOT NULL,
+	charge_point_identification      TEXT NOT NULL,
+	signature_algorithm              TEXT NOT NULL,
+	signature_encoding               TEXT NOT NULL,
+	signature_data                   TEXT NOT NULL,
+	-- Unix time in nanoseconds of the first reading
+	reading_time                     INTEGER,
+	report                           TEXT,
+	stored_at                        INTEGER NOT NULL
+);
+
+CREATE INDEX messages_meter_serial ON messages (meter_serial, reading_time);
+CREATE INDEX messages_pagination ON messages (meter_serial, pagination);
+CREATE INDEX messages_identification_data ON messages (identification_data);
+CREATE INDEX messages_reading_time ON messages (reading_time);
+
+CREATE TABLE readings (
+	message_id        INTEGER NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+	position          INTEGER NOT NULL,
+	time              TEXT NOT NULL,
+	timestamp         INTEGER,
+	time_status       TEXT NOT NULL,
+	transaction_type  TEXT NOT NULL,
+	value             REAL NOT NULL,
+	identifier        TEXT NOT NULL,
+	unit              TEXT NOT NULL,
+	current_type      TEXT NOT NULL,
+	cumulated_loss    REAL NOT NULL,
+	error_flags       TEXT NOT NULL,
+	status            TEXT NOT NULL,
+	PRIMARY KEY (message_id, position)
+);
+
+CREATE INDEX readings_timestamp ON readings (timestamp);
+
+CREATE TABLE sessions (
+	id                  TEXT PRIMARY KEY,
+	meter_serial        TEXT NOT NULL,
+	identification_type TEXT NOT NULL,
+	identification_data TEXT NOT NULL,
+	started_at          INTEGER NOT NULL,
+	ended_at            INTEGER
+);
+
+CREATE INDEX sessions_meter_serial ON sessions (meter_serial, started_at);
+CREATE INDEX sessions_identification_data ON sessions (identification_data);
+CREATE INDEX sessions_started_at ON sessions (started_at);
+
+CREATE TABLE session_messages (
+	session_id TEXT NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+	position   INTEGER NOT NULL,
+	message_id INTEGER NOT NULL REFERENCES messages (id),
+	PRIMARY KEY (session_id, position)
+);
+
+CREATE TABLE meter_keys (
+	meter_serial  TEXT PRIMARY KEY,
+	public_key    BLOB NOT NULL,
+	fingerprint   TEXT NOT NULL,
+	registered_at INTEGER NOT NULL
+);
+`,
+}
+
+func schemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, errors.Wrap(err, "failed to read schema version")
+	}
+
+	return version, nil
+}
+
+// migrate applies the missing migrations, each in its own transaction.
+func migrate(ctx context.Context, db *sql.DB) error {
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return errors.Errorf("database schema version %d is newer than the supported version %d", version, len(migrations))
+	}
+
+	for ; version < len(migrations); version++ {
+		err := withTx(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[version]); err != nil {
+				return err
+			}
+
+			// Pragmas cannot be parameterized
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version+1))
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply migration %d", version+1)
+		}
+	}
+
+	return nil
+}
+
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,526 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"database/sql"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/ChargePi/ocmf-go/store"
+	"github.com/pkg/errors"
+	// Pure Go SQLite driver
+	_ "modernc.org/sqlite"
+)
+
+// Store is a store.Store backed by SQLite. It is safe for concurrent use.
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+var _ store.Store = (*Store)(nil)
+
+// Open opens or creates the database file and migrates it to the latest schema.
+func Open(ctx context.Context, path string) (*Store, error) {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+
+	// SQLite decodes escaped characters in URI file names, so paths may contain "?", "#" or "%"
+	dsn := url.URL{
+		Scheme:   "file",
+		Opaque:   (&url.URL{Path: path}).EscapedPath(),
+		RawQuery: query.Encode(),
+	}
+
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open database")
+	}
+
+	s, err := New(ctx, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// New uses an already opened SQLite database and migrates it to the latest schema.
+// Foreign keys must be enabled on all connections of the database.
+func New(ctx context.Context, db *sql.DB) (*Store, error) {
+	if err := migrate(ctx, db); err != nil {
+		return nil, err
+	}
+
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// SchemaVersion returns the number of migrations applied to the database.
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	return schemaVersion(ctx, s.db)
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) SaveMessage(ctx context.Context, message ocmf.Message, report *ocmf.VerificationReport) (int64, error) {
+	// Keep the exact signed bytes, even if the message was built without them
+	rawPayload := message.RawPayload
+	if len(rawPayload) == 0 {
+		var err error
+		if rawPayload, err = json.Marshal(message.Payload); err != nil {
+			return 0, errors.Wrap(err, "failed to encode payload")
+		}
+	}
+
+	rawSignature := message.RawSignature
+	if len(rawSignature) == 0 {
+		var err error
+		if rawSignature, err = json.Marshal(message.Signature); err != nil {
+			return 0, errors.Wrap(err, "failed to encode signature")
+		}
+	}
+
+	var encodedReport sql.NullString
+	if report != nil {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to encode report")
+		}
+
+		encodedReport = sql.NullString{String: string(data), Valid: true}
+	}
+
+	payload := message.Payload
+	signature := message.Signature.WithDefaults()
+	var id int64
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+INSERT INTO messages (
+	raw_payload, raw_signature, format_version, gateway_id, gateway_serial, gateway_version, pagination,
+	meter_vendor, meter_model, meter_serial, meter_firmware,
+	identification_status, identification_level, identification_type, identification_data, tariff_text,
+	charge_point_identification_type, charge_point_identification,
+	signature_algorithm, signature_encoding, signature_data, reading_time, report, stored_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			rawPayload, rawSignature, payload.FormatVersion, payload.GatewayID, payload.GatewaySerial, payload.GatewayVersion, payload.Pagination,
+			payload.MeterVendor, payload.MeterModel, payload.MeterSerial, payload.MeterFirmware,
+			payload.IdentificationStatus, payload.IdentificationLevel, payload.IdentificationType, payload.IdentificationData, payload.TariffText,
+			payload.ChargePointIdentificationType, payload.ChargePointIdentification,
+			signature.Algorithm, signature.Encoding, signature.Data,
+			nullTime(messageTime(payload)), encodedReport, s.now().UnixNano(),
+		)
+		if err != nil {
+			return err
+		}
+
+		id, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for position, reading := range payload.Readings {
+			timestamp, status, _ := reading.ParseTime()
+			_, err := tx.ExecContext(ctx, `
+INSERT INTO readings (
+	message_id, position, time, timestamp, time_status, transaction_type, value, identifier, unit,
+	current_type, cumulated_loss, error_flags, status
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				id, position, reading.Time, nullTime(timestamp), status, reading.Transaction, reading.ReadingValue, reading.ReadingIdentifier, reading.ReadingUnit,
+				reading.ReadingType, reading.CumulatedLoss, reading.ErrorFlags, reading.Status,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to save message")
+	}
+
+	return id, nil
+}
+
+const messageColumns = "id, raw_payload, raw_signature, reading_time, report, stored_at"
+
+func (s *Store) GetMessage(ctx context.Context, id int64) (*store.StoredMessage, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+messageColumns+" FROM messages WHERE id = ?", id)
+
+	message, err := scanMessage(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(store.ErrNotFound, "message %d", id)
+	}
+
+	return message, err
+}
+
+func (s *Store) FindMessages(ctx context.Context, filter store.MessageFilter) ([]store.StoredMessage, error) {
+	where := conditions{}
+	where.equal("meter_serial", filter.MeterSerial)
+	where.equal("pagination", filter.Pagination)
+	where.equal("identification_data", filter.IdentificationData)
+	where.timeRange("reading_time", filter.From, filter.To)
+
+	query := "SELECT " + messageColumns + " FROM messages" + where.String() + " ORDER BY reading_time, id"
+	args := where.args
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query messages")
+	}
+	defer rows.Close()
+
+	var messages []store.StoredMessage
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, *message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to query messages")
+	}
+
+	return messages, nil
+}
+
+func (s *Store) SaveSession(ctx context.Context, session store.Session) error {
+	if session.ID == "" {
+		return errors.New("session ID is required")
+	}
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+INSERT INTO sessions (id, meter_serial, identification_type, identification_data, started_at, ended_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	meter_serial = excluded.meter_serial,
+	identification_type = excluded.identification_type,
+	identification_data = excluded.identification_data,
+	started_at = excluded.started_at,
+	ended_at = excluded.ended_at`,
+			session.ID, session.MeterSerial, session.IdentificationType, session.IdentificationData,
+			session.StartedAt.UnixNano(), nullTime(session.EndedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM session_messages WHERE session_id = ?", session.ID); err != nil {
+			return err
+		}
+
+		for position, messageID := range session.MessageIDs {
+			_, err := tx.ExecContext(ctx, "INSERT INTO session_messages (session_id, position, message_id) VALUES (?, ?, ?)",
+				session.ID, position, messageID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return errors.Wrap(err, "failed to save session")
+}
+
+const sessionColumns = "id, meter_serial, identification_type, identification_data, started_at, ended_at"
+
+func (s *Store) GetSession(ctx context.Context, id string) (*store.Session, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+sessionColumns+" FROM sessions WHERE id = ?", id)
+
+	session, err := scanSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(store.ErrNotFound, "session %s", id)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadSessionMessages(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *Store) FindSessions(ctx context.Context, filter store.SessionFilter) ([]store.Session, error) {
+	where := conditions{}
+	where.equal("meter_serial", filter.MeterSerial)
+	where.equal("identification_data", filter.IdentificationData)
+	where.timeRange("started_at", filter.From, filter.To)
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+sessionColumns+" FROM sessions"+where.String()+" ORDER BY started_at, id", where.args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query sessions")
+	}
+
+	var sessions []store.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to query sessions")
+	}
+
+	// Loaded after the rows are closed, so that a single connection is enough
+	for i := range sessions {
+		if err := s.loadSessionMessages(ctx, &sessions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return sessions, nil
+}
+
+func (s *Store) loadSessionMessages(ctx context.Context, session *store.Session) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT message_id FROM session_messages WHERE session_id = ? ORDER BY position", session.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to query session messages")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var messageID int64
+		if err := rows.Scan(&messageID); err != nil {
+			return errors.Wrap(err, "failed to read session messages")
+		}
+
+		session.MessageIDs = append(session.MessageIDs, messageID)
+	}
+
+	return errors.Wrap(rows.Err(), "failed to read session messages")
+}
+
+func (s *Store) SaveKey(ctx context.Context, key store.MeterKey) error {
+	if key.MeterSerial == "" {
+		return errors.New("meter serial is required")
+	}
+
+	der, err := ocmf.MarshalPublicKey(key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	fingerprint, err := ocmf.KeyFingerprint(key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	registeredAt := key.RegisteredAt
+	if registeredAt.IsZero() {
+		registeredAt = s.now()
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+INSERT INTO meter_keys (meter_serial, public_key, fingerprint, registered_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (meter_serial) DO UPDATE SET
+	public_key = excluded.public_key,
+	fingerprint = excluded.fingerprint,
+	registered_at = excluded.registered_at`,
+		key.MeterSerial, der, fingerprint, registeredAt.UnixNano(),
+	)
+
+	return errors.Wrap(err, "failed to save key")
+}
+
+func (s *Store) GetKey(ctx context.Context, meterSerial string) (*store.MeterKey, error) {
+	var (
+		der          []byte
+		registeredAt int64
+	)
+
+	key := store.MeterKey{MeterSerial: meterSerial}
+	err := s.db.QueryRowContext(ctx, "SELECT public_key, fingerprint, registered_at FROM meter_keys WHERE meter_serial = ?", meterSerial).
+		Scan(&der, &key.Fingerprint, &registeredAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, errors.Wrapf(store.ErrNotFound, "key of meter %s", meterSerial)
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to get key")
+	}
+
+	key.PublicKey, err = ocmf.ParsePublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	key.RegisteredAt = time.Unix(0, registeredAt)
+	return &key, nil
+}
+
+func (s *Store) DeleteKey(ctx context.Context, meterSerial string) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM meter_keys WHERE meter_serial = ?", meterSerial)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete key")
+	}
+
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return errors.Wrapf(store.ErrNotFound, "key of meter %s", meterSerial)
+	}
+
+	return nil
+}
+
+// ResolvePublicKey implements ocmf.KeyResolver. Unknown meters are reported with ocmf.ErrKeyNotFound.
+func (s *Store) ResolvePublicKey(ctx context.Context, meterSerial string) (*ecdsa.PublicKey, error) {
+	key, err := s.GetKey(ctx, meterSerial)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, errors.Wrapf(ocmf.ErrKeyNotFound, "meter %s", meterSerial)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key.PublicKey, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMessage(row scanner) (*store.StoredMessage, error) {
+	var (
+		stored       store.StoredMessage
+		rawPayload   []byte
+		rawSignature []byte
+		readingTime  sql.NullInt64
+		report       sql.NullString
+		storedAt     int64
+	)
+
+	err := row.Scan(&stored.ID, &rawPayload, &rawSignature, &readingTime, &report, &storedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "failed to read message")
+	}
+
+	// The decoded columns are only used for queries, the message is restored from the signed bytes
+	message, err := ocmf.ParseMessage("OCMF|" + string(rawPayload) + "|" + string(rawSignature))
+	if err != nil {
+		return nil, errors.Wrapf(err, "message %d", stored.ID)
+	}
+
+	stored.Message = *message
+	stored.StoredAt = time.Unix(0, storedAt)
+	if readingTime.Valid {
+		stored.Time = time.Unix(0, readingTime.Int64)
+	}
+
+	if report.Valid {
+		stored.Report = &ocmf.VerificationReport{}
+		if err := json.Unmarshal([]byte(report.String), stored.Report); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode report of message %d", stored.ID)
+		}
+	}
+
+	return &stored, nil
+}
+
+func scanSession(row scanner) (*store.Session, error) {
+	var (
+		session   store.Session
+		startedAt int64
+		endedAt   sql.NullInt64
+	)
+
+	err := row.Scan(&session.ID, &session.MeterSerial, &session.IdentificationType, &session.IdentificationData, &startedAt, &endedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "failed to read session")
+	}
+
+	session.StartedAt = time.Unix(0, startedAt)
+	if endedAt.Valid {
+		session.EndedAt = time.Unix(0, endedAt.Int64)
+	}
+
+	return &session, nil
+}
+
+// messageTime returns the time of the first readable reading.
+func messageTime(payload ocmf.PayloadSection) time.Time {
+	for _, reading := range payload.Readings {
+		if timestamp, _, err := reading.ParseTime(); err == nil {
+			return timestamp
+		}
+	}
+
+	return time.Time{}
+}
+
+func nullTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+// conditions builds the WHERE clause of a query. Empty values are skipped.
+type conditions struct {
+	clauses []string
+	args    []any
+}
+
+func (c *conditions) equal(column, value string) {
+	if value == "" {
+		return
+	}
+
+	c.clauses = append(c.clauses, column+" = ?")
+	c.args = append(c.args, value)
+}
+
+func (c *conditions) timeRange(column string, from, to time.Time) {
+	if !from.IsZero() {
+		c.clauses = append(c.clauses, column+" >= ?")
+		c.args = append(c.args, from.UnixNano())
+	}
+
+	if !to.IsZero() {
+		c.clauses = append(c.clauses, column+" < ?")
+		c.args = append(c.args, to.UnixNano())
+	}
+}
+
+func (c *conditions) String() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
@@ -0,0 +1,371 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/ChargePi/ocmf-go/store"
+	"github.com/stretchr/testify/suite"
+)
+
+type sqliteTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	path       string
+	store      *Store
+}
+
+func (s *sqliteTestSuite) SetupTest() {
+	privateKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp256r1SHA256)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.path = filepath.Join(s.T().TempDir(), "ocmf.db")
+	s.store, err = Open(context.Background(), s.path)
+	s.Require().NoError(err)
+}
+
+func (s *sqliteTestSuite) TearDownTest() {
+	_ = s.store.Close()
+}
+
+func (s *sqliteTestSuite) buildMessage(meterSerial string, counter int, day int, idData string) *ocmf.Message {
+	builder := ocmf.NewBuilder(s.privateKey).
+		WithPagination(fmt.Sprintf("T%d", counter)).
+		WithMeterSerial(meterSerial).
+		WithIdentificationType(string(ocmf.RfidPlain)).
+		WithIdentificationData(idData).
+		AddReading(ocmf.Reading{
+			Time:         fmt.Sprintf("2024-03-%02dT10:00:00,000+0100 S", day),
+			Transaction:  string(ocmf.TransactionBegin),
+			ReadingValue: float64(counter),
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		})
+
+	message, err := builder.Build()
+	s.Require().NoError(err)
+	return message
+}
+
+func (s *sqliteTestSuite) TestMigrations() {
+	ctx := context.Background()
+
+	version, err := s.store.SchemaVersion(ctx)
+	s.Require().NoError(err)
+	s.Equal(len(migrations), version)
+
+	// Reopening does not apply the migrations again
+	s.Require().NoError(s.store.Close())
+	s.store, err = Open(ctx, s.path)
+	s.Require().NoError(err)
+
+	indexes := map[string]bool{}
+	rows, err := s.store.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'index' AND name NOT LIKE 'sqlite_%'")
+	s.Require().NoError(err)
+	for rows.Next() {
+		var name string
+		s.Require().NoError(rows.Scan(&name))
+		indexes[name] = true
+	}
+	s.Require().NoError(rows.Close())
+
+	for _, index := range []string{"messages_meter_serial", "messages_pagination", "messages_identification_data", "messages_reading_time"} {
+		s.True(indexes[index], index)
+	}
+
+	// A database created by a newer version is not modified
+	_, err = s.store.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", len(migrations)+1))
+	s.Require().NoError(err)
+	s.Require().NoError(s.store.Close())
+
+	_, err = Open(ctx, s.path)
+	s.ErrorContains(err, "newer than the supported version")
+}
+
+func (s *sqliteTestSuite) TestOpenPath() {
+	ctx := context.Background()
+
+	for _, name := range []string{"ocmf?mode=ro.db", "ocmf#1.db", "100%.db", "with space.db"} {
+		s.T().Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			store, err := Open(ctx, path)
+			s.Require().NoError(err)
+
+			_, err = store.SaveMessage(ctx, *s.buildMessage("meter1", 1, 1, "AABBCCDD"), nil)
+			s.Require().NoError(err)
+			s.Require().NoError(store.Close())
+
+			// The database is created with the exact name and can be opened again
+			s.FileExists(path)
+			store, err = Open(ctx, path)
+			s.Require().NoError(err)
+			defer store.Close()
+
+			version, err := store.SchemaVersion(ctx)
+			s.Require().NoError(err)
+			s.Equal(len(migrations), version)
+		})
+	}
+}
+
+func (s *sqliteTestSuite) TestSaveMessage() {
+	ctx := context.Background()
+
+	// Signed bytes as sent by a meter, which differ from what json.Marshal would produce
+	signature := ocmf.NewDefaultSignature()
+	rawPayload := []byte("{\n \"FV\": \"1.0\", \"PG\": \"T7\", \"MS\": \"meterA\", \"IT\": \"NONE\",\n \"RD\": [{\"TM\": \"2024-03-01T10:00:00,000+0100 S\", \"RV\": 1.50, \"RU\": \"kWh\", \"ST\": \"G\"}]\n}")
+	s.Require().NoError(signature.SignBytes(rawPayload, s.privateKey))
+
+	message, err := ocmf.ParseMessage("OCMF|" + string(rawPayload) + `|{"SD":"` + signature.Data + `"}`)
+	s.Require().NoError(err)
+
+	id, err := s.store.SaveMessage(ctx, *message, message.Report(&s.privateKey.PublicKey))
+	s.Require().NoError(err)
+
+	stored, err := s.store.GetMessage(ctx, id)
+	s.Require().NoError(err)
+	s.Equal(id, stored.ID)
+	s.Equal(message.String(), stored.Message.String())
+	s.Equal(rawPayload, stored.Message.RawPayload)
+	s.Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), stored.Time.UTC())
+	s.WithinDuration(time.Now(), stored.StoredAt, time.Minute)
+	s.Require().NotNil(stored.Report)
+	s.Equal(ocmf.VerdictValid, stored.Report.Verdict)
+
+	valid, err := stored.Message.Verify(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.True(valid)
+
+	// Decoded columns
+	var (
+		meterSerial string
+		readingUnit string
+		value       float64
+	)
+	err = s.store.db.QueryRowContext(ctx, `
+SELECT m.meter_serial, r.unit, r.value FROM messages m JOIN readings r ON r.message_id = m.id WHERE m.id = ?`, id).
+		Scan(&meterSerial, &readingUnit, &value)
+	s.Require().NoError(err)
+	s.Equal("meterA", meterSerial)
+	s.Equal("kWh", readingUnit)
+	s.Equal(1.5, value)
+
+	// Without a report
+	id, err = s.store.SaveMessage(ctx, *s.buildMessage("meterA", 8, 1, "AABB"), nil)
+	s.Require().NoError(err)
+
+	stored, err = s.store.GetMessage(ctx, id)
+	s.Require().NoError(err)
+	s.Nil(stored.Report)
+
+	_, err = s.store.GetMessage(ctx, 1000)
+	s.ErrorIs(err, store.ErrNotFound)
+}
+
+func (s *sqliteTestSuite) TestFindMessages() {
+	ctx := context.Background()
+
+	for day := 1; day <= 3; day++ {
+		for _, meterSerial := range []string{"meterA", "meterB"} {
+			_, err := s.store.SaveMessage(ctx, *s.buildMessage(meterSerial, day, day, fmt.Sprintf("0%d", day)), nil)
+			s.Require().NoError(err)
+		}
+	}
+
+	from := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   store.MessageFilter
+		expected []string
+	}{
+		{
+			name:     "All messages",
+			filter:   store.MessageFilter{},
+			expected: []string{"meterA T1", "meterB T1", "meterA T2", "meterB T2", "meterA T3", "meterB T3"},
+		},
+		{
+			name:     "Meter serial",
+			filter:   store.MessageFilter{MeterSerial: "meterB"},
+			expected: []string{"meterB T1", "meterB T2", "meterB T3"},
+		},
+		{
+			name:     "Pagination",
+			filter:   store.MessageFilter{MeterSerial: "meterA", Pagination: "T2"},
+			expected: []string{"meterA T2"},
+		},
+		{
+			name:     "Identification data",
+			filter:   store.MessageFilter{IdentificationData: "03"},
+			expected: []string{"meterA T3", "meterB T3"},
+		},
+		{
+			name:     "Time range",
+			filter:   store.MessageFilter{From: from, To: to},
+			expected: []string{"meterA T2", "meterB T2"},
+		},
+		{
+			name:     "Limit",
+			filter:   store.MessageFilter{From: from, Limit: 3},
+			expected: []string{"meterA T2", "meterB T2", "meterA T3"},
+		},
+		{
+			name:   "No match",
+			filter: store.MessageFilter{MeterSerial: "meterC"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			messages, err := s.store.FindMessages(ctx, tt.filter)
+			s.Require().NoError(err)
+
+			var actual []string
+			for _, message := range messages {
+				actual = append(actual, message.Message.Payload.MeterSerial+" "+message.Message.Payload.Pagination)
+			}
+
+			s.Equal(tt.expected, actual)
+		})
+	}
+}
+
+func (s *sqliteTestSuite) TestSessions() {
+	ctx := context.Background()
+
+	begin, err := s.store.SaveMessage(ctx, *s.buildMessage("meterA", 1, 1, "AABB"), nil)
+	s.Require().NoError(err)
+	end, err := s.store.SaveMessage(ctx, *s.buildMessage("meterA", 2, 1, "AABB"), nil)
+	s.Require().NoError(err)
+
+	startedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	session := store.Session{
+		ID:                 "42",
+		MeterSerial:        "meterA",
+		IdentificationType: string(ocmf.RfidPlain),
+		IdentificationData: "AABB",
+		StartedAt:          startedAt,
+		MessageIDs:         []int64{begin},
+	}
+	s.Require().NoError(s.store.SaveSession(ctx, session))
+
+	stored, err := s.store.GetSession(ctx, "42")
+	s.Require().NoError(err)
+	s.True(stored.EndedAt.IsZero())
+	s.Equal([]int64{begin}, stored.MessageIDs)
+	s.True(startedAt.Equal(stored.StartedAt))
+
+	// The session ends
+	session.EndedAt = startedAt.Add(time.Hour)
+	session.MessageIDs = append(session.MessageIDs, end)
+	s.Require().NoError(s.store.SaveSession(ctx, session))
+
+	stored, err = s.store.GetSession(ctx, "42")
+	s.Require().NoError(err)
+	s.True(session.EndedAt.Equal(stored.EndedAt))
+	s.Equal([]int64{begin, end}, stored.MessageIDs)
+
+	s.Require().NoError(s.store.SaveSession(ctx, store.Session{ID: "43", MeterSerial: "meterB", StartedAt: startedAt.Add(24 * time.Hour)}))
+
+	sessions, err := s.store.FindSessions(ctx, store.SessionFilter{})
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 2)
+	s.Equal("42", sessions[0].ID)
+	s.Equal([]int64{begin, end}, sessions[0].MessageIDs)
+
+	sessions, err = s.store.FindSessions(ctx, store.SessionFilter{IdentificationData: "AABB"})
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+
+	sessions, err = s.store.FindSessions(ctx, store.SessionFilter{From: startedAt.Add(time.Hour)})
+	s.Require().NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal("43", sessions[0].ID)
+
+	_, err = s.store.GetSession(ctx, "44")
+	s.ErrorIs(err, store.ErrNotFound)
+
+	// Messages must exist
+	s.Error(s.store.SaveSession(ctx, store.Session{ID: "45", MessageIDs: []int64{1000}}))
+	s.Error(s.store.SaveSession(ctx, store.Session{}))
+}
+
+func (s *sqliteTestSuite) TestKeys() {
+	ctx := context.Background()
+
+	s.Require().NoError(s.store.SaveKey(ctx, store.MeterKey{MeterSerial: "meterA", PublicKey: &s.privateKey.PublicKey}))
+
+	key, err := s.store.GetKey(ctx, "meterA")
+	s.Require().NoError(err)
+	s.True(s.privateKey.PublicKey.Equal(key.PublicKey))
+	s.WithinDuration(time.Now(), key.RegisteredAt, time.Minute)
+
+	fingerprint, err := ocmf.KeyFingerprint(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+	s.Equal(fingerprint, key.Fingerprint)
+
+	// The store resolves the keys for the parser
+	message := s.buildMessage("meterA", 1, 1, "AABB")
+	_, err = ocmf.NewParser(ocmf.WithKeyResolver(s.store)).ParseMessage(*message).GetMessageContext(ctx)
+	s.NoError(err)
+
+	_, err = s.store.ResolvePublicKey(ctx, "meterB")
+	s.ErrorIs(err, ocmf.ErrKeyNotFound)
+
+	// Replace the key
+	otherKey, err := ocmf.GenerateKey(ocmf.SignatureAlgorithmECDSAsecp384r1SHA256)
+	s.Require().NoError(err)
+	s.Require().NoError(s.store.SaveKey(ctx, store.MeterKey{MeterSerial: "meterA", PublicKey: &otherKey.PublicKey}))
+
+	publicKey, err := s.store.ResolvePublicKey(ctx, "meterA")
+	s.Require().NoError(err)
+	s.True(otherKey.PublicKey.Equal(publicKey))
+
+	s.Require().NoError(s.store.DeleteKey(ctx, "meterA"))
+	_, err = s.store.GetKey(ctx, "meterA")
+	s.ErrorIs(err, store.ErrNotFound)
+	s.ErrorIs(s.store.DeleteKey(ctx, "meterA"), store.ErrNotFound)
+
+	s.Error(s.store.SaveKey(ctx, store.MeterKey{MeterSerial: "meterA"}))
+}
+
+func (s *sqliteTestSuite) TestConcurrentWrites() {
+	ctx := context.Background()
+	message := s.buildMessage("meterA", 1, 1, "AABB")
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 40)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				_, err := s.store.SaveMessage(ctx, *message, nil)
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.Require().NoError(err)
+	}
+
+	messages, err := s.store.FindMessages(ctx, store.MessageFilter{MeterSerial: "meterA"})
+	s.Require().NoError(err)
+	s.Len(messages, 40)
+}
+
+func TestSQLiteStore(t *testing.T) {
+	suite.Run(t, new(sqliteTestSuite))
+}
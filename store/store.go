@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"time"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+)
+
+// StoredMessage is a message with the metadata of the store.
+type StoredMessage struct {
+	ID      int64
+	Message ocmf.Message
+	// Report is the verification report the message was stored with, if any.
+	Report *ocmf.VerificationReport
+	// Time is the time of the first reading of the message, or zero if it cannot be parsed.
+	Time     time.Time
+	StoredAt time.Time
+}
+
+// MessageFilter selects stored messages. Empty fields match all messages.
+type MessageFilter struct {
+	MeterSerial        string
+	Pagination         string
+	IdentificationData string
+	// From and To limit the messages by the time of their first reading. From is inclusive, To is exclusive.
+	From time.Time
+	To   time.Time
+	// Limit is the maximum number of messages returned. Zero returns all messages.
+	Limit int
+}
+
+// Session is a charging session, i.e. the messages of one transaction.
+type Session struct {
+	// ID identifies the session, e.g. the transaction ID of the charge point.
+	ID                 string
+	MeterSerial        string
+	IdentificationType string
+	IdentificationData string
+	StartedAt          time.Time
+	// EndedAt is zero while the session is running.
+	EndedAt time.Time
+	// MessageIDs are the IDs of the stored messages of the session, in order.
+	MessageIDs []int64
+}
+
+// SessionFilter selects stored sessions. Empty fields match all sessions.
+type SessionFilter struct {
+	MeterSerial        string
+	IdentificationData string
+	// From and To limit the sessions by their start time. From is inclusive, To is exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// MeterKey is the public key registered for a meter.
+type MeterKey struct {
+	MeterSerial string
+	PublicKey   *ecdsa.PublicKey
+	// Fingerprint is set by the store, see ocmf.KeyFingerprint.
+	Fingerprint  string
+	RegisteredAt time.Time
+}
+
+// Store persists messages, sessions and meter keys. Messages are stored with their raw signed bytes,
+// so they can be verified again after being read from the store.
+// A Store also resolves the public keys of meters, so it can be used with ocmf.SelectPublicKey and the parser.
+type Store interface {
+	ocmf.KeyResolver
+
+	// SaveMessage stores the message with its verification report, which may be nil, and returns its ID.
+	SaveMessage(ctx context.Context, message ocmf.Message, report *ocmf.VerificationReport) (int64, error)
+	GetMessage(ctx context.Context, id int64) (*StoredMessage, error)
+	// FindMessages returns the messages matching the filter, ordered by reading time.
+	FindMessages(ctx context.Context, filter MessageFilter) ([]StoredMessage, error)
+
+	// SaveSession creates or replaces the session with the same ID.
+	SaveSession(ctx context.Context, session Session) error
+	GetSession(ctx context.Context, id string) (*Session, error)
+	// FindSessions returns the sessions matching the filter, ordered by start time.
+	FindSessions(ctx context.Context, filter SessionFilter) ([]Session, error)
+
+	// SaveKey registers or replaces the key of the meter.
+	SaveKey(ctx context.Context, key MeterKey) error
+	GetKey(ctx context.Context, meterSerial string) (*MeterKey, error)
+	DeleteKey(ctx context.Context, meterSerial string) error
+
+	Close() error
+}
@@ -0,0 +1,72 @@
+package ocmf_go
+
+import "slices"
+
+// Template holds the meter and gateway identity together with the signing configuration.
+// A Template cannot be modified after it is created, so it is safe to share between goroutines.
+// Every call to NewBuilder returns an independent Builder for a single message.
+type Template struct {
+	payload           PayloadSection
+	signature         Signature
+	signer            Signer
+	paginationManager *PaginationManager
+	paginationKind    PaginationKind
+	hooks             hooks
+	errs              []error
+}
+
+// Template captures the identity fields and signing configuration of the builder.
+// Per-message fields (pagination, user identification, tariff text and readings) are not part of the template.
+func (b *Builder) Template() *Template {
+	return &Template{
+		payload: PayloadSection{
+			FormatVersion:                 b.payload.FormatVersion,
+			GatewayID:                     b.payload.GatewayID,
+			GatewaySerial:                 b.payload.GatewaySerial,
+			GatewayVersion:                b.payload.GatewayVersion,
+			MeterVendor:                   b.payload.MeterVendor,
+			MeterModel:                    b.payload.MeterModel,
+			MeterSerial:                   b.payload.MeterSerial,
+			MeterFirmware:                 b.payload.MeterFirmware,
+			LossCompensation:              b.payload.LossCompensation,
+			ChargeControllerVersion:       b.payload.ChargeControllerVersion,
+			ChargePointIdentificationType: b.payload.ChargePointIdentificationType,
+			ChargePointIdentification:     b.payload.ChargePointIdentification,
+		},
+		signature: Signature{
+			Algorithm: b.signature.Algorithm,
+			Encoding:  b.signature.Encoding,
+			MimeType:  b.signature.MimeType,
+		},
+		signer:            b.signer,
+		paginationManager: b.paginationManager,
+		paginationKind:    b.paginationKind,
+		hooks:             b.hooks,
+		errs:              slices.Clone(b.errs),
+	}
+}
+
+// NewBuilder returns a Builder pre-filled with the template identity.
+func (t *Template) NewBuilder() *Builder {
+	return &Builder{
+		payload:           t.payload,
+		signature:         t.signature,
+		signer:            t.signer,
+		paginationManager: t.paginationManager,
+		paginationKind:    t.paginationKind,
+		hooks:             t.hooks,
+		errs:              slices.Clone(t.errs),
+	}
+}
+
+func (t *Template) MeterSerial() string {
+	return t.payload.MeterSerial
+}
+
+func (t *Template) SignatureAlgorithm() SignatureAlgorithm {
+	return t.signature.Algorithm
+}
+
+func (t *Template) SignatureEncoding() SignatureEncoding {
+	return t.signature.Encoding
+}
@@ -0,0 +1,106 @@
+package ocmf_go
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type templateTestSuite struct {
+	suite.Suite
+}
+
+func (s *templateTestSuite) TestTemplate() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	template := NewBuilder(privateKey, WithSignatureEncoding(SignatureEncodingBase64)).
+		WithGatewayID("ABL SBC-301").
+		WithMeterVendor("Phoenix Contact").
+		WithMeterSerial("BQ27400330016").
+		// Per-message fields are not captured by the template
+		WithPagination("T1").
+		WithIdentificationType(string(RfidPlain)).
+		AddReading(Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			ReadingValue: 123,
+			ReadingUnit:  string(UnitskWh),
+			Status:       string(MeterOk),
+		}).
+		Template()
+
+	s.Equal("BQ27400330016", template.MeterSerial())
+	s.Equal(SignatureEncodingBase64, template.SignatureEncoding())
+	s.Equal(SignatureAlgorithmECDSAsecp256r1SHA256, template.SignatureAlgorithm())
+
+	builder := template.NewBuilder()
+	s.Equal("ABL SBC-301", builder.payload.GatewayID)
+	s.Equal("Phoenix Contact", builder.payload.MeterVendor)
+	s.Equal("BQ27400330016", builder.payload.MeterSerial)
+	s.Empty(builder.payload.Pagination)
+	s.Empty(builder.payload.IdentificationType)
+	s.Empty(builder.payload.Readings)
+	s.Empty(builder.signature.Data)
+}
+
+func (s *templateTestSuite) TestTemplate_IndependentMessages() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	manager := NewPaginationManager(nil)
+	template := NewBuilder(privateKey, WithPaginationManager(manager, PaginationTransaction)).
+		WithMeterSerial("exampleSerial123").
+		Template()
+
+	// Assertions are made after all builders finished, as the goroutines must not stop the test
+	payloads := make([]*PayloadSection, 20)
+	errs := make([]error, 20)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			message, err := template.NewBuilder().
+				WithIdentificationType(string(RfidNone)).
+				AddReading(Reading{
+					Time:         "2018-07-24T13:22:04,000+0200 S",
+					ReadingValue: float64(i + 1),
+					ReadingUnit:  string(UnitskWh),
+					Status:       string(MeterOk),
+				}).
+				Build()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			parser := NewParser(WithAutomaticSignatureVerification(&privateKey.PublicKey)).ParseOcmfMessageFromString(message.String())
+			payloads[i], errs[i] = parser.GetPayload()
+			if errs[i] == nil {
+				_, errs[i] = parser.GetSignature()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range payloads {
+		s.Require().NoError(errs[i], fmt.Sprintf("message %d", i))
+		s.Require().Len(payloads[i].Readings, 1, fmt.Sprintf("message %d", i))
+		s.Equal(float64(i+1), payloads[i].Readings[0].ReadingValue)
+	}
+
+	counters, err := manager.Current("exampleSerial123")
+	s.Require().NoError(err)
+	s.EqualValues(20, counters.Transaction)
+}
+
+func TestTemplate(t *testing.T) {
+	suite.Run(t, new(templateTestSuite))
+}
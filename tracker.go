@@ -0,0 +1,152 @@
+package ocmf_go
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type TrackerEventType string
+
+const (
+	// TrackerEventDuplicate is reported when a message with an already seen pagination counter is received again.
+	TrackerEventDuplicate = TrackerEventType("DUPLICATE")
+	// TrackerEventGap is reported when one or more pagination counters are missing.
+	TrackerEventGap = TrackerEventType("GAP")
+	// TrackerEventPaginationRollback is reported when the pagination counter went backwards.
+	TrackerEventPaginationRollback = TrackerEventType("PAGINATION_ROLLBACK")
+	// TrackerEventTimeRollback is reported when a reading is older than a previously seen reading.
+	TrackerEventTimeRollback = TrackerEventType("TIME_ROLLBACK")
+	// TrackerEventRegisterRollback is reported when a register value is lower than a previously seen value.
+	TrackerEventRegisterRollback = TrackerEventType("REGISTER_ROLLBACK")
+)
+
+// TrackerEvent describes a single anomaly detected while ingesting a message.
+type TrackerEvent struct {
+	Type        TrackerEventType `json:"type"`
+	MeterSerial string           `json:"meterSerial"`
+	Pagination  string           `json:"pagination"`
+	// Previous is the last value seen before the anomaly (pagination, time or register value).
+	Previous string `json:"previous,omitempty"`
+	// Current is the value that triggered the event.
+	Current string `json:"current,omitempty"`
+	// ReadingIndex is the index of the reading in the message, or -1 if the event concerns the whole message.
+	ReadingIndex int    `json:"readingIndex"`
+	Message      string `json:"message"`
+}
+
+type meterState struct {
+	pagination map[PaginationKind]Pagination
+	lastTime   time.Time
+	lastTimeTM string
+	registers  map[string]float64
+}
+
+// Tracker keeps the history of received messages per meter serial and reports duplicates, gaps and rollbacks.
+// It is safe for concurrent use.
+type Tracker struct {
+	mu     sync.Mutex
+	meters map[string]*meterState
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		meters: make(map[string]*meterState),
+	}
+}
+
+// Ingest checks the payload against the state of its meter and updates the state.
+// Duplicated or rolled back messages do not advance the pagination state.
+func (t *Tracker) Ingest(payload PayloadSection) ([]TrackerEvent, error) {
+	if payload.MeterSerial == "" {
+		return nil, errors.New("meter serial is required")
+	}
+
+	pagination, err := ParsePagination(payload.Pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, found := t.meters[payload.MeterSerial]
+	if !found {
+		state = &meterState{
+			pagination: make(map[PaginationKind]Pagination),
+			registers:  make(map[string]float64),
+		}
+		t.meters[payload.MeterSerial] = state
+	}
+
+	newEvent := func(eventType TrackerEventType, readingIndex int, previous, current, message string) TrackerEvent {
+		return TrackerEvent{
+			Type:         eventType,
+			MeterSerial:  payload.MeterSerial,
+			Pagination:   payload.Pagination,
+			Previous:     previous,
+			Current:      current,
+			ReadingIndex: readingIndex,
+			Message:      message,
+		}
+	}
+
+	events := []TrackerEvent{}
+
+	previous, seen := state.pagination[pagination.Kind]
+	if seen {
+		err := CheckPaginationSequence(previous, *pagination)
+		switch {
+		case errors.Is(err, ErrPaginationReplay) && pagination.Counter == previous.Counter:
+			events = append(events, newEvent(TrackerEventDuplicate, -1, previous.String(), pagination.String(),
+				"message was already received"))
+			return events, nil
+		case errors.Is(err, ErrPaginationReplay):
+			events = append(events, newEvent(TrackerEventPaginationRollback, -1, previous.String(), pagination.String(),
+				"pagination counter went backwards"))
+			return events, nil
+		case errors.Is(err, ErrPaginationGap):
+			events = append(events, newEvent(TrackerEventGap, -1, previous.String(), pagination.String(),
+				fmt.Sprintf("%d message(s) missing", pagination.Counter-previous.Counter-1)))
+		case err != nil:
+			return nil, err
+		}
+	}
+	state.pagination[pagination.Kind] = *pagination
+
+	for i, reading := range payload.Readings {
+		readingTime, timeStatus, err := reading.ParseTime()
+		// Relative timestamps are not comparable with absolute ones
+		if err == nil && timeStatus != TimeStatusRelative {
+			if !state.lastTime.IsZero() && readingTime.Before(state.lastTime) {
+				events = append(events, newEvent(TrackerEventTimeRollback, i, state.lastTimeTM, reading.Time,
+					"reading is older than a previously received reading"))
+			} else {
+				state.lastTime = readingTime
+				state.lastTimeTM = reading.Time
+			}
+		}
+
+		register := reading.ReadingIdentifier + "|" + reading.ReadingUnit
+		lastValue, found := state.registers[register]
+		if found && reading.ReadingValue < lastValue {
+			events = append(events, newEvent(TrackerEventRegisterRollback, i,
+				fmt.Sprint(lastValue), fmt.Sprint(reading.ReadingValue),
+				"register value is lower than a previously received value"))
+		} else {
+			state.registers[register] = reading.ReadingValue
+		}
+	}
+
+	return events, nil
+}
+
+// Reset forgets everything known about the meter, e.g. after a meter exchange.
+func (t *Tracker) Reset(meterSerial string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.meters, meterSerial)
+}
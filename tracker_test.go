@@ -0,0 +1,158 @@
+package ocmf_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type trackerTestSuite struct {
+	suite.Suite
+}
+
+func newTrackerPayload(pagination string, readings ...Reading) PayloadSection {
+	return PayloadSection{
+		FormatVersion:      OcmfVersion,
+		Pagination:         pagination,
+		MeterSerial:        "exampleSerial123",
+		IdentificationType: string(RfidNone),
+		Readings:           readings,
+	}
+}
+
+func newTrackerReading(time string, value float64) Reading {
+	return Reading{
+		Time:              time,
+		ReadingValue:      value,
+		ReadingIdentifier: "1-b:1.8.0",
+		ReadingUnit:       string(UnitskWh),
+		Status:            string(MeterOk),
+	}
+}
+
+func (s *trackerTestSuite) TestIngest_valid() {
+	tracker := NewTracker()
+
+	events, err := tracker.Ingest(newTrackerPayload("T1", newTrackerReading("2018-07-24T13:22:04,000+0200 S", 10)))
+	s.Require().NoError(err)
+	s.Empty(events)
+
+	events, err = tracker.Ingest(newTrackerPayload("T2", newTrackerReading("2018-07-24T13:26:04,000+0200 S", 12)))
+	s.Require().NoError(err)
+	s.Empty(events)
+
+	// Fiscal counters are tracked separately
+	events, err = tracker.Ingest(newTrackerPayload("F1", newTrackerReading("2018-07-24T13:27:04,000+0200 S", 12)))
+	s.Require().NoError(err)
+	s.Empty(events)
+}
+
+func (s *trackerTestSuite) TestIngest_anomalies() {
+	tests := []struct {
+		name     string
+		second   PayloadSection
+		expected []TrackerEventType
+	}{
+		{
+			name:     "Duplicate",
+			second:   newTrackerPayload("T5", newTrackerReading("2018-07-24T13:22:04,000+0200 S", 10)),
+			expected: []TrackerEventType{TrackerEventDuplicate},
+		},
+		{
+			name:     "Pagination rollback",
+			second:   newTrackerPayload("T3", newTrackerReading("2018-07-24T13:30:04,000+0200 S", 11)),
+			expected: []TrackerEventType{TrackerEventPaginationRollback},
+		},
+		{
+			name:     "Gap",
+			second:   newTrackerPayload("T8", newTrackerReading("2018-07-24T13:30:04,000+0200 S", 11)),
+			expected: []TrackerEventType{TrackerEventGap},
+		},
+		{
+			name:     "Time rollback",
+			second:   newTrackerPayload("T6", newTrackerReading("2018-07-24T13:20:04,000+0200 S", 11)),
+			expected: []TrackerEventType{TrackerEventTimeRollback},
+		},
+		{
+			name:     "Register rollback",
+			second:   newTrackerPayload("T6", newTrackerReading("2018-07-24T13:30:04,000+0200 S", 9)),
+			expected: []TrackerEventType{TrackerEventRegisterRollback},
+		},
+		{
+			name:     "Relative time is not compared",
+			second:   newTrackerPayload("T6", newTrackerReading("2018-07-24T13:20:04,000+0200 R", 11)),
+			expected: []TrackerEventType{},
+		},
+		{
+			name: "Gap and rollbacks",
+			second: newTrackerPayload("T9",
+				newTrackerReading("2018-07-24T13:20:04,000+0200 S", 9),
+			),
+			expected: []TrackerEventType{TrackerEventGap, TrackerEventTimeRollback, TrackerEventRegisterRollback},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker()
+
+			events, err := tracker.Ingest(newTrackerPayload("T5", newTrackerReading("2018-07-24T13:22:04,000+0200 S", 10)))
+			s.Require().NoError(err)
+			s.Empty(events)
+
+			events, err = tracker.Ingest(tt.second)
+			s.Require().NoError(err)
+
+			eventTypes := []TrackerEventType{}
+			for _, event := range events {
+				s.Equal("exampleSerial123", event.MeterSerial)
+				eventTypes = append(eventTypes, event.Type)
+			}
+			s.Equal(tt.expected, eventTypes)
+		})
+	}
+}
+
+func (s *trackerTestSuite) TestIngest_duplicateDoesNotAdvanceState() {
+	tracker := NewTracker()
+
+	_, err := tracker.Ingest(newTrackerPayload("T1", newTrackerReading("2018-07-24T13:22:04,000+0200 S", 10)))
+	s.Require().NoError(err)
+
+	events, err := tracker.Ingest(newTrackerPayload("T1", newTrackerReading("2018-07-24T13:22:04,000+0200 S", 10)))
+	s.Require().NoError(err)
+	s.Len(events, 1)
+
+	events, err = tracker.Ingest(newTrackerPayload("T2", newTrackerReading("2018-07-24T13:24:04,000+0200 S", 11)))
+	s.Require().NoError(err)
+	s.Empty(events)
+}
+
+func (s *trackerTestSuite) TestIngest_invalid() {
+	tracker := NewTracker()
+
+	_, err := tracker.Ingest(newTrackerPayload("1"))
+	s.ErrorIs(err, ErrInvalidPagination)
+
+	payload := newTrackerPayload("T1")
+	payload.MeterSerial = ""
+	_, err = tracker.Ingest(payload)
+	s.Error(err)
+}
+
+func (s *trackerTestSuite) TestReset() {
+	tracker := NewTracker()
+
+	_, err := tracker.Ingest(newTrackerPayload("T5", newTrackerReading("2018-07-24T13:22:04,000+0200 S", 10)))
+	s.Require().NoError(err)
+
+	tracker.Reset("exampleSerial123")
+
+	events, err := tracker.Ingest(newTrackerPayload("T1", newTrackerReading("2018-07-24T13:20:04,000+0200 S", 1)))
+	s.Require().NoError(err)
+	s.Empty(events)
+}
+
+func TestTracker(t *testing.T) {
+	suite.Run(t, new(trackerTestSuite))
+}
@@ -0,0 +1,190 @@
+package transparency
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	FormatOCMF = "OCMF"
+
+	EncodingPlain  = "plain"
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+)
+
+var (
+	ErrUnsupportedFormat   = errors.New("unsupported signed data format")
+	ErrUnsupportedEncoding = errors.New("unsupported encoding")
+)
+
+// Entry is a single OCMF message with the public key of the meter, as stored in a transparency software container.
+type Entry struct {
+	Message ocmf.Message
+	// PublicKey is optional. Without it, the transparency software asks the user for the key.
+	PublicKey *ecdsa.PublicKey
+	// TransactionID and Context are optional attributes of the value.
+	TransactionID string
+	Context       string
+}
+
+type xmlValues struct {
+	XMLName xml.Name   `xml:"values"`
+	Values  []xmlValue `xml:"value"`
+}
+
+type xmlValue struct {
+	TransactionID string        `xml:"transactionId,attr,omitempty"`
+	Context       string        `xml:"context,attr,omitempty"`
+	SignedData    xmlSignedData `xml:"signedData"`
+	PublicKey     *xmlPublicKey `xml:"publicKey,omitempty"`
+}
+
+type xmlSignedData struct {
+	Format   string `xml:"format,attr"`
+	Encoding string `xml:"encoding,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+type xmlPublicKey struct {
+	Encoding string `xml:"encoding,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+// Export writes the entries as an XML container readable by the S.A.F.E. transparency software.
+// Public keys are written as hex encoded DER, the format shown on meter displays and charge point labels.
+func Export(w io.Writer, entries []Entry) error {
+	values := xmlValues{}
+	for i, entry := range entries {
+		value := xmlValue{
+			TransactionID: entry.TransactionID,
+			Context:       entry.Context,
+			SignedData: xmlSignedData{
+				Format:   FormatOCMF,
+				Encoding: EncodingPlain,
+				Value:    entry.Message.String(),
+			},
+		}
+
+		if entry.PublicKey != nil {
+			der, err := ocmf.MarshalPublicKey(entry.PublicKey)
+			if err != nil {
+				return errors.Wrapf(err, "entry %d", i)
+			}
+
+			value.PublicKey = &xmlPublicKey{
+				Encoding: EncodingPlain,
+				Value:    strings.ToUpper(hex.EncodeToString(der)),
+			}
+		}
+
+		values.Values = append(values.Values, value)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to write XML header")
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(values)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode XML")
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// Import reads an XML container and parses every OCMF message in it. The messages are not verified.
+func Import(r io.Reader) ([]Entry, error) {
+	values := xmlValues{}
+	err := xml.NewDecoder(r).Decode(&values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode XML")
+	}
+
+	entries := []Entry{}
+	for i, value := range values.Values {
+		entry, err := importValue(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "value %d", i)
+		}
+
+		entries = append(entries, *entry)
+	}
+
+	return entries, nil
+}
+
+func importValue(value xmlValue) (*Entry, error) {
+	if !strings.EqualFold(value.SignedData.Format, FormatOCMF) {
+		return nil, errors.Wrapf(ErrUnsupportedFormat, "%q", value.SignedData.Format)
+	}
+
+	data, err := decode(value.SignedData.Encoding, value.SignedData.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode signed data")
+	}
+
+	message, err := ocmf.ParseMessage(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Message:       *message,
+		TransactionID: value.TransactionID,
+		Context:       value.Context,
+	}
+
+	if value.PublicKey != nil && strings.TrimSpace(value.PublicKey.Value) != "" {
+		// ParsePublicKey detects hex and base64 on its own
+		publicKey, err := ocmf.ParsePublicKey([]byte(value.PublicKey.Value))
+		if err != nil {
+			return nil, err
+		}
+
+		entry.PublicKey = publicKey
+	}
+
+	return entry, nil
+}
+
+func decode(encoding, value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(encoding) {
+	case EncodingPlain, "":
+		return []byte(value), nil
+	case EncodingBase64:
+		return base64.StdEncoding.DecodeString(value)
+	case EncodingHex:
+		return hex.DecodeString(value)
+	default:
+		return nil, errors.Wrapf(ErrUnsupportedEncoding, "%q", encoding)
+	}
+}
+
+// Verify validates and verifies the message of the entry. The key is selected with ocmf.SelectPublicKey,
+// so the key stored in the container is only trusted if no resolver is given.
+func (e Entry) Verify(ctx context.Context, resolver ocmf.KeyResolver) (*ocmf.Message, error) {
+	publicKey, err := ocmf.SelectPublicKey(ctx, resolver, e.Message.Payload.MeterSerial, e.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return ocmf.NewParser(
+		ocmf.WithAutomaticValidation(),
+		ocmf.WithAutomaticSignatureVerification(publicKey),
+	).ParseMessage(e.Message).GetMessage()
+}
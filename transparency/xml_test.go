@@ -0,0 +1,163 @@
+package transparency
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	ocmf "github.com/ChargePi/ocmf-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type xmlTestSuite struct {
+	suite.Suite
+	privateKey *ecdsa.PrivateKey
+	message    *ocmf.Message
+}
+
+func (s *xmlTestSuite) SetupTest() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.privateKey = privateKey
+
+	s.message, err = ocmf.NewBuilder(privateKey).
+		WithPagination("T1").
+		WithMeterSerial("exampleSerial123").
+		WithIdentificationType(string(ocmf.RfidNone)).
+		WithTariffText("Tarif <1> & \"2\"").
+		AddReading(ocmf.Reading{
+			Time:         "2018-07-24T13:22:04,000+0200 S",
+			Transaction:  "B",
+			ReadingValue: 10,
+			ReadingUnit:  string(ocmf.UnitskWh),
+			Status:       string(ocmf.MeterOk),
+		}).
+		Build()
+	s.Require().NoError(err)
+}
+
+func (s *xmlTestSuite) TestExportImport() {
+	entries := []Entry{
+		{
+			Message:       *s.message,
+			PublicKey:     &s.privateKey.PublicKey,
+			TransactionID: "42",
+			Context:       "Transaction.Begin",
+		},
+		{
+			Message: *s.message,
+		},
+	}
+
+	buffer := bytes.Buffer{}
+	s.Require().NoError(Export(&buffer, entries))
+	s.True(strings.HasPrefix(buffer.String(), "<?xml"))
+	s.Contains(buffer.String(), `<signedData format="OCMF" encoding="plain">OCMF|`)
+	s.Contains(buffer.String(), `<publicKey encoding="plain">3059`)
+
+	imported, err := Import(&buffer)
+	s.Require().NoError(err)
+	s.Require().Len(imported, 2)
+	s.Equal(*s.message, imported[0].Message)
+	s.True(s.privateKey.PublicKey.Equal(imported[0].PublicKey))
+	s.Equal("42", imported[0].TransactionID)
+	s.Equal("Transaction.Begin", imported[0].Context)
+	s.Nil(imported[1].PublicKey)
+
+	message, err := imported[0].Verify(context.Background(), nil)
+	s.Require().NoError(err)
+	s.Equal(*s.message, *message)
+
+	_, err = imported[1].Verify(context.Background(), nil)
+	s.ErrorIs(err, ocmf.ErrPublicKeyMissing)
+
+	registry := ocmf.NewKeyRegistry()
+	registry.Register("exampleSerial123", &s.privateKey.PublicKey)
+	_, err = imported[1].Verify(context.Background(), registry)
+	s.NoError(err)
+}
+
+func (s *xmlTestSuite) TestImport_encodings() {
+	der, err := ocmf.MarshalPublicKey(&s.privateKey.PublicKey)
+	s.Require().NoError(err)
+
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<values>
+  <value>
+    <signedData format="OCMF" encoding="base64">` + base64.StdEncoding.EncodeToString(s.message.Bytes()) + `</signedData>
+    <publicKey encoding="base64">` + base64.StdEncoding.EncodeToString(der) + `</publicKey>
+  </value>
+  <value>
+    <signedData format="ocmf" encoding="hex">` + hex.EncodeToString(s.message.Bytes()) + `</signedData>
+    <publicKey>
+      ` + hex.EncodeToString(der) + `
+    </publicKey>
+  </value>
+</values>`
+
+	entries, err := Import(strings.NewReader(data))
+	s.Require().NoError(err)
+	s.Require().Len(entries, 2)
+
+	for _, entry := range entries {
+		_, err = entry.Verify(context.Background(), nil)
+		s.NoError(err)
+	}
+}
+
+func (s *xmlTestSuite) TestImport_invalid() {
+	tests := []struct {
+		name  string
+		data  string
+		error error
+		text  string
+	}{
+		{
+			name: "Malformed XML",
+			data: "<values><value>",
+			text: "failed to decode XML",
+		},
+		{
+			name:  "Unsupported format",
+			data:  `<values><value><signedData format="EDL">data</signedData></value></values>`,
+			error: ErrUnsupportedFormat,
+		},
+		{
+			name:  "Unsupported encoding",
+			data:  `<values><value><signedData format="OCMF" encoding="zip">data</signedData></value></values>`,
+			error: ErrUnsupportedEncoding,
+		},
+		{
+			name:  "Invalid message",
+			data:  `<values><value><signedData format="OCMF">OCMF|{}</signedData></value></values>`,
+			error: ocmf.ErrInvalidFormat,
+		},
+		{
+			name: "Invalid public key",
+			data: `<values><value><signedData format="OCMF">` + s.message.String() + `</signedData>` +
+				`<publicKey>ABCD</publicKey></value></values>`,
+			error: ocmf.ErrInvalidPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			_, err := Import(strings.NewReader(tt.data))
+			if tt.error != nil {
+				s.ErrorIs(err, tt.error)
+			} else {
+				s.ErrorContains(err, tt.text)
+			}
+		})
+	}
+}
+
+func TestXML(t *testing.T) {
+	suite.Run(t, new(xmlTestSuite))
+}
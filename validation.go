@@ -1,14 +1,21 @@
 package ocmf_go
 
 import (
+	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
 )
 
 var messageValidator = validator.New()
 
 func init() {
+	// Report fields by their OCMF names, e.g. RD[0].TM instead of Readings[0].Time
+	messageValidator.RegisterTagNameFunc(jsonFieldName)
+
 	// Register custom validators for the validator
 	must(messageValidator.RegisterValidation("meterError", meterErrorValidator))
 	must(messageValidator.RegisterValidation("userAssignmentState", userAssignmentStateValidator))
@@ -21,6 +28,7 @@ func init() {
 	must(messageValidator.RegisterValidation("unit", unitValidator))
 	must(messageValidator.RegisterValidation("currentType", currentTypeValidator))
 	must(messageValidator.RegisterValidation("iso8601", iso8601WithMillisValidator))
+	must(messageValidator.RegisterValidation("transactionType", transactionTypeValidator))
 }
 
 func must(err error) {
@@ -29,6 +37,117 @@ func must(err error) {
 	}
 }
 
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// FieldError describes a single validation rule a field did not satisfy.
+type FieldError struct {
+	// Field is the path of the field using the OCMF names, e.g. "RD[0].TM".
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Value   any    `json:"value"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// FieldErrors extracts the field level diagnostics from an error returned by Validate, with English messages.
+// It returns nil if the error does not contain validation errors.
+func FieldErrors(err error) []FieldError {
+	return LocalizedFieldErrors(err, LanguageEnglish)
+}
+
+// LocalizedFieldErrors is FieldErrors with messages in the given language, so they can be shown to end users.
+func LocalizedFieldErrors(err error, language Language) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fieldErrors := []FieldError{}
+	for _, validationError := range validationErrors {
+		// Strip the struct name from the namespace
+		_, field, found := strings.Cut(validationError.Namespace(), ".")
+		if !found {
+			field = validationError.Field()
+		}
+
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   field,
+			Rule:    validationError.Tag(),
+			Param:   validationError.Param(),
+			Value:   validationError.Value(),
+			Message: fieldErrorMessage(field, validationError, language),
+		})
+	}
+
+	return fieldErrors
+}
+
+// enumRules maps the validation rules of enumerations to the descriptions of their values.
+var enumRules = map[string]interface{ list(Language) string }{
+	"meterError":            meterErrorDescriptions,
+	"userAssignmentState":   userAssignmentStateDescriptions,
+	"ocppState":             ocppStateDescriptions,
+	"rfidState":             rfidStateDescriptions,
+	"timeStatus":            timeStatusDescriptions,
+	"transactionType":       transactionTypeDescriptions,
+	"iso5118State":          iso15118StateDescriptions,
+	"chargePointAssignment": chargePointAssignmentTypeDescriptions,
+	"unit":                  unitsDescriptions,
+	"currentType":           currentTypeDescriptions,
+}
+
+var fieldErrorMessages = map[Language]map[string]string{
+	LanguageEnglish: {
+		"required": "%s is required",
+		"max":      "%s must not be longer than %s",
+		"oneof":    "%s must be one of [%s], got %q",
+		"enum":     "%s has an invalid value %q, expected one of: %s",
+		"default":  "%s has an invalid value %q for rule '%s'",
+	},
+	LanguageGerman: {
+		"required": "%s ist erforderlich",
+		"max":      "%s darf nicht länger als %s sein",
+		"oneof":    "%s muss einer der Werte [%s] sein, erhalten: %q",
+		"enum":     "%s hat einen ungültigen Wert %q, erwartet wird einer von: %s",
+		"default":  "%s hat einen ungültigen Wert %q für die Regel '%s'",
+	},
+}
+
+func fieldErrorMessage(field string, validationError validator.FieldError, language Language) string {
+	messages, found := fieldErrorMessages[language]
+	if !found {
+		messages = fieldErrorMessages[LanguageEnglish]
+	}
+
+	value := fmt.Sprint(validationError.Value())
+
+	switch validationError.Tag() {
+	case "required":
+		return fmt.Sprintf(messages["required"], field)
+	case "max":
+		return fmt.Sprintf(messages["max"], field, validationError.Param())
+	case "oneof":
+		return fmt.Sprintf(messages["oneof"], field, validationError.Param(), value)
+	}
+
+	if enum, isEnum := enumRules[validationError.Tag()]; isEnum {
+		return fmt.Sprintf(messages["enum"], field, value, enum.list(language))
+	}
+
+	return fmt.Sprintf(messages["default"], field, value, validationError.Tag())
+}
+
 func meterErrorValidator(fl validator.FieldLevel) bool {
 	return isValidMeterError(MeterError(fl.Field().String()))
 }
@@ -71,6 +190,10 @@ func currentTypeValidator(fl validator.FieldLevel) bool {
 	return isValidCurrentType(CurrentType(fl.Field().String()))
 }
 
+func transactionTypeValidator(fl validator.FieldLevel) bool {
+	return isValidTransactionType(TransactionType(fl.Field().String()))
+}
+
 var iso8601WithMillisRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2},\d{3}[+-]\d{4} [S|U|I|R]$`)
 
 func iso8601WithMillisValidator(fl validator.FieldLevel) bool {
@@ -82,6 +205,8 @@ func iso8601WithMillisValidator(fl validator.FieldLevel) bool {
 var signatureValidator = validator.New()
 
 func init() {
+	signatureValidator.RegisterTagNameFunc(jsonFieldName)
+
 	// Register custom validators for the validator
 	must(signatureValidator.RegisterValidation("signatureAlgorithm", signatureAlgorithmValidator))
 	must(signatureValidator.RegisterValidation("signatureEncoding", signatureEncodingValidator))
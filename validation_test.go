@@ -1,6 +1,10 @@
 package ocmf_go
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestMeterErrorValidator(t *testing.T) {
 	tests := []struct {
@@ -130,3 +134,69 @@ func TestCurrentTypeValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldErrors(t *testing.T) {
+	payload := PayloadSection{
+		Pagination:         "1",
+		IdentificationType: string(RfidNone),
+		Readings: []Reading{
+			{
+				Time:         "2018-07-24T13:22:04,000+0200 S",
+				ReadingValue: 1,
+				ReadingUnit:  string(UnitskWh),
+				Status:       string(MeterOk),
+			},
+			{
+				Time:         "2018-07-24T13:22:04Z",
+				ReadingValue: 1,
+				ReadingUnit:  string(UnitskWh),
+				Status:       "Z",
+			},
+		},
+	}
+
+	fieldErrors := FieldErrors(payload.Validate())
+	assert.Equal(t, []FieldError{
+		{
+			Field:   "PG",
+			Rule:    "pagination",
+			Value:   "1",
+			Message: `PG has an invalid value "1" for rule 'pagination'`,
+		},
+		{
+			Field:   "MS",
+			Rule:    "required",
+			Value:   "",
+			Message: "MS is required",
+		},
+		{
+			Field:   "RD[1].TM",
+			Rule:    "iso8601",
+			Value:   "2018-07-24T13:22:04Z",
+			Message: `RD[1].TM has an invalid value "2018-07-24T13:22:04Z" for rule 'iso8601'`,
+		},
+		{
+			Field: "RD[1].ST",
+			Rule:  "meterError",
+			Value: "Z",
+			Message: `RD[1].ST has an invalid value "Z", expected one of: N (Not present), G (OK), T (Timeout), ` +
+				`D (Disconnected), R (Removed), M (Manipulated), X (Exchanged), I (Incompatible), O (Out of range), ` +
+				`S (Substitute value), E (Other error), F (Read error)`,
+		},
+	}, fieldErrors)
+
+	signature := Signature{Algorithm: SignatureAlgorithmECDSAsecp256r1SHA256, Encoding: SignatureEncodingHex, MimeType: "text/plain"}
+	fieldErrors = FieldErrors(signature.Validate())
+	assert.Len(t, fieldErrors, 2)
+	assert.Equal(t, "SM", fieldErrors[0].Field)
+	assert.Equal(t, "oneof", fieldErrors[0].Rule)
+	assert.Equal(t, "SD", fieldErrors[1].Field)
+
+	assert.Nil(t, FieldErrors(nil))
+	assert.Nil(t, FieldErrors(ErrInvalidFormat))
+
+	fieldErrors = LocalizedFieldErrors(payload.Validate(), LanguageGerman)
+	assert.Len(t, fieldErrors, 4)
+	assert.Equal(t, "MS ist erforderlich", fieldErrors[1].Message)
+	assert.Contains(t, fieldErrors[3].Message, `RD[1].ST hat einen ungültigen Wert "Z", erwartet wird einer von: N (Nicht vorhanden), G (In Ordnung)`)
+}